@@ -0,0 +1,121 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/bobcob7/polly-bot/internal/auth"
+	"github.com/bobcob7/polly-bot/internal/models"
+	"github.com/bobcob7/polly-bot/pkg/discord"
+	"github.com/bufbuild/connect-go"
+	"github.com/bwmarrin/discordgo"
+	"github.com/upper/db/v4"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// mirrorBuffer is how many events can wait to be mirrored before new ones are dropped.
+const mirrorBuffer = 64
+
+// Recorder stores audit events and mirrors them to a Discord channel.
+type Recorder struct {
+	logger    *zap.Logger
+	sess      db.Session
+	channelID string
+	mirror    chan *models.AuditEvent
+}
+
+var _ discord.Auditor = &Recorder{}
+
+// NewRecorder creates a Recorder, events are only mirrored if channelID is set.
+func NewRecorder(sess db.Session, channelID string) *Recorder {
+	return &Recorder{
+		logger:    zap.L().With(zap.String("component", "audit")),
+		sess:      sess,
+		channelID: channelID,
+		mirror:    make(chan *models.AuditEvent, mirrorBuffer),
+	}
+}
+
+// Audit stores an event from the bot.
+func (r *Recorder) Audit(ctx context.Context, event *discord.AuditEvent) {
+	r.record(ctx, &models.AuditEvent{
+		Source:  models.AuditSourceDiscord,
+		UserID:  event.UserID,
+		GuildID: event.GuildID,
+		Action:  event.Action,
+		Options: event.Options,
+		Result:  event.Result,
+		Error:   event.Error,
+	})
+}
+
+// record stores the event, failing to do so is logged rather than failing the audited action.
+func (r *Recorder) record(ctx context.Context, event *models.AuditEvent) {
+	if err := event.Create(ctx, r.sess); err != nil {
+		r.logger.Error("failed to record audit event", zap.Error(err), zap.String("action", event.Action))
+	}
+	if r.channelID == "" {
+		return
+	}
+	select {
+	case r.mirror <- event:
+	default:
+		r.logger.Warn("dropped audit event mirror", zap.String("action", event.Action))
+	}
+}
+
+// OnStart mirrors audit events to the channel until the bot stops.
+func (r *Recorder) OnStart(ctx discord.Context, s *discordgo.Session) error {
+	if r.channelID == "" {
+		return nil
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-r.mirror:
+			if _, err := s.ChannelMessageSend(r.channelID, event.String()); err != nil {
+				r.logger.Error("failed to mirror audit event", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Interceptor audits every unary RPC, after the handler returns.
+// It should run before auth.Interceptor so RPCs it denies a scope are audited as well,
+// events still record the caller auth.Interceptor authenticated rather than its address.
+// Unauthenticated and rate limited RPCs are left to auth.Interceptor's logs,
+// otherwise anyone could flood the table and the mirror channel.
+func (r *Recorder) Interceptor() connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			ctx, caller := auth.WithCaller(ctx)
+			res, err := next(ctx, req)
+			if caller.Throttled() {
+				return res, err
+			}
+			event := &models.AuditEvent{
+				Source: models.AuditSourceRPC,
+				UserID: req.Peer().Addr,
+				Action: req.Spec().Procedure,
+				Result: models.AuditResultOK,
+			}
+			if principal, ok := caller.Principal(); ok {
+				event.UserID = principal.ID
+			}
+			if msg, ok := req.Any().(proto.Message); ok {
+				if options, encodeErr := protojson.Marshal(msg); encodeErr == nil {
+					event.Options = string(options)
+				}
+			}
+			if err != nil {
+				event.Result = models.AuditResultError
+				event.Error = err.Error()
+			}
+			// The request context may already be cancelled, which shouldn't lose the event
+			r.record(context.Background(), event)
+			return res, err
+		}
+	}
+}
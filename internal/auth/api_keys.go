@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bobcob7/polly-bot/internal/models"
+	"github.com/bufbuild/connect-go"
+	"github.com/upper/db/v4"
+	"go.uber.org/zap"
+)
+
+// lastUsedResolution limits how often a key's last use is written to the database.
+const lastUsedResolution = time.Minute
+
+// APIKeys authenticates callers sending "Authorization: Bearer <key>" with a key from the database.
+type APIKeys struct {
+	logger *zap.Logger
+	sess   db.Session
+}
+
+var _ Authenticator = &APIKeys{}
+
+func NewAPIKeys(sess db.Session) *APIKeys {
+	return &APIKeys{
+		logger: zap.L().With(zap.String("component", "auth")),
+		sess:   sess,
+	}
+}
+
+func (a *APIKeys) Authenticate(ctx context.Context, header http.Header) (*Principal, error) {
+	token, ok := strings.CutPrefix(header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return nil, nil
+	}
+	id, secret, ok := models.ParseAPIKey(strings.TrimSpace(token))
+	if !ok {
+		return nil, connect.NewError(connect.CodeUnauthenticated, errInvalidAPIKey)
+	}
+	key, err := models.GetAPIKey(ctx, a.sess, id)
+	if err != nil {
+		if errors.Is(err, db.ErrNoMoreRows) {
+			return nil, connect.NewError(connect.CodeUnauthenticated, errInvalidAPIKey)
+		}
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	if !key.Verify(secret) || key.Revoked() {
+		return nil, connect.NewError(connect.CodeUnauthenticated, errInvalidAPIKey)
+	}
+	if key.LastUsedAt == nil || time.Since(*key.LastUsedAt) > lastUsedResolution {
+		if err := key.Used(ctx, a.sess); err != nil {
+			a.logger.Error("failed to record api key use", zap.Error(err), zap.String("key", key.ID))
+		}
+	}
+	return &Principal{
+		ID:        "key:" + key.ID,
+		Scopes:    key.ScopeList(),
+		RateLimit: key.RateLimit,
+	}, nil
+}
@@ -0,0 +1,162 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/bobcob7/polly-bot/internal/models"
+	"github.com/bufbuild/connect-go"
+	"go.uber.org/zap"
+)
+
+// Principal is an authenticated RPC caller.
+type Principal struct {
+	// ID identifies the caller in audit events and rate limits, e.g. "key:0123abcd".
+	ID     string
+	Scopes []string
+	// RateLimit is how many RPCs per minute the caller can make, zero uses the default.
+	RateLimit int
+}
+
+// Authenticator identifies the caller of an RPC.
+// It returns nil without an error when the request carries none of its credentials,
+// so the next Authenticator can be tried.
+type Authenticator interface {
+	Authenticate(ctx context.Context, header http.Header) (*Principal, error)
+}
+
+type principalKey struct{}
+
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// FromContext returns the caller authenticated by the Interceptor.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok
+}
+
+type callerKey struct{}
+
+// Caller is filled in with the principal once the Interceptor authenticates the RPC,
+// so interceptors which run before it, like auditing, can tell who called.
+type Caller struct {
+	principal *Principal
+	throttled bool
+}
+
+// WithCaller returns a context for the rest of the interceptor chain,
+// the Caller is only filled in if the RPC is authenticated.
+func WithCaller(ctx context.Context) (context.Context, *Caller) {
+	caller := &Caller{}
+	return context.WithValue(ctx, callerKey{}, caller), caller
+}
+
+// Principal returns the caller once the Interceptor authenticated it.
+func (c *Caller) Principal() (*Principal, bool) {
+	return c.principal, c.principal != nil
+}
+
+// Throttled reports whether the Interceptor rejected the RPC as unauthenticated or rate limited.
+// These are only logged, since anyone can send them as fast as they like.
+func (c *Caller) Throttled() bool {
+	return c.throttled
+}
+
+// Interceptor authenticates every RPC with the first Authenticator to recognize its credentials,
+// then checks the caller has the scope the procedure requires and is within its rate limit.
+// Procedures missing from scopes require the admin scope.
+type Interceptor struct {
+	logger           *zap.Logger
+	scopes           map[string]string
+	defaultRateLimit int
+	authenticators   []Authenticator
+	limits           *limiter
+}
+
+var _ connect.Interceptor = &Interceptor{}
+
+func NewInterceptor(scopes map[string]string, defaultRateLimit int, authenticators ...Authenticator) *Interceptor {
+	return &Interceptor{
+		logger:           zap.L().With(zap.String("component", "auth")),
+		scopes:           scopes,
+		defaultRateLimit: defaultRateLimit,
+		authenticators:   authenticators,
+		limits:           newLimiter(),
+	}
+}
+
+func (i *Interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		ctx, err := i.authorize(ctx, req.Spec().Procedure, req.Peer().Addr, req.Header())
+		if err != nil {
+			return nil, err
+		}
+		return next(ctx, req)
+	}
+}
+
+// WrapStreamingClient leaves outgoing streams alone, Polly only serves RPCs.
+func (i *Interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler authorizes a stream once when it opens, each message isn't rate limited.
+func (i *Interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		ctx, err := i.authorize(ctx, conn.Spec().Procedure, conn.Peer().Addr, conn.RequestHeader())
+		if err != nil {
+			return err
+		}
+		return next(ctx, conn)
+	}
+}
+
+// authorize returns ctx with the authenticated principal, or the error to reject the RPC with.
+func (i *Interceptor) authorize(ctx context.Context, procedure, peer string, header http.Header) (context.Context, error) {
+	logger := i.logger.With(zap.String("procedure", procedure), zap.String("peer", peer))
+	caller, ok := ctx.Value(callerKey{}).(*Caller)
+	if !ok {
+		caller = &Caller{}
+	}
+	principal, err := authenticate(ctx, header, i.authenticators)
+	if err != nil {
+		logger.Warn("rejected unauthenticated RPC", zap.Error(err))
+		caller.throttled = true
+		return nil, err
+	}
+	caller.principal = principal
+	logger = logger.With(zap.String("principal", principal.ID))
+	required, ok := i.scopes[procedure]
+	if !ok {
+		required = models.ScopeAdmin
+	}
+	if !models.ScopesAllow(principal.Scopes, required) {
+		logger.Warn("rejected unauthorized RPC", zap.String("scope", required))
+		return nil, connect.NewError(connect.CodePermissionDenied, missingScopeError{required})
+	}
+	rateLimit := principal.RateLimit
+	if rateLimit == 0 {
+		rateLimit = i.defaultRateLimit
+	}
+	if !i.limits.Allow(principal.ID, rateLimit) {
+		logger.Warn("rejected rate limited RPC")
+		caller.throttled = true
+		return nil, connect.NewError(connect.CodeResourceExhausted, errRateLimited)
+	}
+	return WithPrincipal(ctx, principal), nil
+}
+
+func authenticate(ctx context.Context, header http.Header, authenticators []Authenticator) (*Principal, error) {
+	for _, authenticator := range authenticators {
+		principal, err := authenticator.Authenticate(ctx, header)
+		if err != nil {
+			return nil, err
+		}
+		if principal != nil {
+			return principal, nil
+		}
+	}
+	return nil, connect.NewError(connect.CodeUnauthenticated, errMissingCredentials)
+}
@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/bobcob7/polly-bot/internal/models"
+	"github.com/bufbuild/connect-go"
+)
+
+// headerAuthenticator grants the read scope to callers sending the test header.
+type headerAuthenticator struct{}
+
+func (headerAuthenticator) Authenticate(ctx context.Context, header http.Header) (*Principal, error) {
+	if header.Get("Test-Caller") == "" {
+		return nil, nil
+	}
+	return &Principal{ID: header.Get("Test-Caller"), Scopes: []string{models.ScopeRead}}, nil
+}
+
+func TestInterceptor_authorize(t *testing.T) {
+	t.Parallel()
+	scopes := map[string]string{"/read": models.ScopeRead}
+	tests := map[string]struct {
+		procedure     string
+		caller        string
+		wantCode      connect.Code
+		wantCaller    bool
+		wantThrottled bool
+	}{
+		"Allowed": {
+			procedure:  "/read",
+			caller:     "reader",
+			wantCaller: true,
+		},
+		"Unauthenticated": {
+			procedure:     "/read",
+			wantCode:      connect.CodeUnauthenticated,
+			wantThrottled: true,
+		},
+		"Missing scope": {
+			procedure:  "/admin",
+			caller:     "reader",
+			wantCode:   connect.CodePermissionDenied,
+			wantCaller: true,
+		},
+	}
+	for name, tt := range tests {
+		testData := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			interceptor := NewInterceptor(scopes, 0, headerAuthenticator{})
+			header := http.Header{}
+			if testData.caller != "" {
+				header.Set("Test-Caller", testData.caller)
+			}
+			ctx, caller := WithCaller(context.Background())
+			ctx, err := interceptor.authorize(ctx, testData.procedure, "peer", header)
+			var code connect.Code
+			if err != nil {
+				code = connect.CodeOf(err)
+			}
+			if code != testData.wantCode {
+				t.Fatalf("Interceptor.authorize() error = %v, want code %v", err, testData.wantCode)
+			}
+			if err == nil {
+				if principal, ok := FromContext(ctx); !ok || principal.ID != testData.caller {
+					t.Errorf("Interceptor.authorize() context principal = %v, want %v", principal, testData.caller)
+				}
+			}
+			if _, ok := caller.Principal(); ok != testData.wantCaller {
+				t.Errorf("Interceptor.authorize() filled caller = %v, want %v", ok, testData.wantCaller)
+			}
+			if caller.Throttled() != testData.wantThrottled {
+				t.Errorf("Interceptor.authorize() throttled caller = %v, want %v", caller.Throttled(), testData.wantThrottled)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"context"
+	"crypto/x509"
+	"net/http"
+)
+
+type certificatesKey struct{}
+
+// Middleware makes the verified client certificate of TLS requests available to ClientCertificates.
+// Connect interceptors can't see the TLS connection, so it has to wrap the HTTP handler.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS != nil && len(r.TLS.VerifiedChains) != 0 && len(r.TLS.VerifiedChains[0]) != 0 {
+			r = r.WithContext(context.WithValue(r.Context(), certificatesKey{}, r.TLS.VerifiedChains[0][0]))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ClientCertificates authenticates callers presenting a client certificate the TLS server verified.
+type ClientCertificates struct {
+	scopes    []string
+	rateLimit int
+}
+
+var _ Authenticator = &ClientCertificates{}
+
+// NewClientCertificates grants every verified certificate the same scopes and rate limit.
+func NewClientCertificates(scopes []string, rateLimit int) *ClientCertificates {
+	return &ClientCertificates{
+		scopes:    scopes,
+		rateLimit: rateLimit,
+	}
+}
+
+func (c *ClientCertificates) Authenticate(ctx context.Context, _ http.Header) (*Principal, error) {
+	certificate, ok := ctx.Value(certificatesKey{}).(*x509.Certificate)
+	if !ok {
+		return nil, nil
+	}
+	return &Principal{
+		ID:        "cert:" + certificate.Subject.CommonName,
+		Scopes:    c.scopes,
+		RateLimit: c.rateLimit,
+	}, nil
+}
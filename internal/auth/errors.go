@@ -0,0 +1,20 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	errMissingCredentials = errors.New("an api key or client certificate is required")
+	errInvalidAPIKey      = errors.New("invalid api key")
+	errRateLimited        = errors.New("rate limit exceeded, try again later")
+)
+
+type missingScopeError struct {
+	scope string
+}
+
+func (m missingScopeError) Error() string {
+	return fmt.Sprintf("the %q scope is required", m.scope)
+}
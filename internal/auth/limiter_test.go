@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_limiter_Allow(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		perMinute int
+		elapsed   time.Duration
+		requests  int
+		want      int
+	}{
+		"Unlimited": {
+			perMinute: 0,
+			requests:  100,
+			want:      100,
+		},
+		"Burst": {
+			perMinute: 10,
+			requests:  15,
+			want:      10,
+		},
+		"Refilled": {
+			perMinute: 10,
+			elapsed:   30 * time.Second,
+			requests:  10,
+			want:      5,
+		},
+	}
+	for name, tt := range tests {
+		testData := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			now := time.Unix(0, 0)
+			l := newLimiter()
+			l.now = func() time.Time { return now }
+			if testData.elapsed != 0 {
+				// Empty the bucket, then wait for it to refill
+				for l.Allow("key", testData.perMinute) {
+				}
+				now = now.Add(testData.elapsed)
+			}
+			var got int
+			for i := 0; i < testData.requests; i++ {
+				if l.Allow("key", testData.perMinute) {
+					got++
+				}
+			}
+			if got != testData.want {
+				t.Errorf("limiter.Allow() allowed %d requests, want %d", got, testData.want)
+			}
+			if !l.Allow("other", testData.perMinute) {
+				t.Errorf("limiter.Allow() limited a different key")
+			}
+		})
+	}
+}
+
+func Test_limiter_prune(t *testing.T) {
+	t.Parallel()
+	now := time.Unix(0, 0)
+	l := newLimiter()
+	l.now = func() time.Time { return now }
+	l.Allow("idle", 10)
+	now = now.Add(30 * time.Second)
+	l.Allow("active", 10)
+	now = now.Add(40 * time.Second)
+	l.Allow("active", 10)
+	if _, ok := l.buckets["idle"]; ok {
+		t.Errorf("limiter.Allow() kept the idle bucket")
+	}
+	if _, ok := l.buckets["active"]; !ok {
+		t.Errorf("limiter.Allow() pruned the active bucket")
+	}
+}
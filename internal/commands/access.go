@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"github.com/bobcob7/polly-bot/internal/config"
+	"github.com/bobcob7/polly-bot/internal/models"
+	"github.com/bobcob7/polly-bot/pkg/discord"
+	"github.com/upper/db/v4"
+	"go.uber.org/zap"
+)
+
+// adminScope is how much of Polly a user administers.
+type adminScope int
+
+const (
+	notAdmin adminScope = iota
+	// guildAdmin administers the guild the interaction came from, through the guild's admin roles.
+	guildAdmin
+	// globalAdmin administers every guild, through the configured admin users and roles.
+	globalAdmin
+)
+
+func getAdminScope(ctx discord.Context, sess db.Session, access config.Access) adminScope {
+	if access.IsAdmin(ctx.UserID(), ctx.MemberRoles()) {
+		return globalAdmin
+	}
+	guildID := ctx.Interaction.GuildID
+	if guildID == "" {
+		return notAdmin
+	}
+	settings, err := models.GetGuildSettings(ctx, sess, guildID)
+	if err != nil {
+		ctx.Logger().Error("failed to get guild settings", zap.Error(err))
+		return notAdmin
+	}
+	guildAccess := config.Access{AdminRoles: settings.AdminRoleIDs()}
+	if guildAccess.IsAdmin(ctx.UserID(), ctx.MemberRoles()) {
+		return guildAdmin
+	}
+	return notAdmin
+}
+
+// administers reports whether the scope covers the guild,
+// only global admins administer other guilds and anything outside of a guild.
+func (s adminScope) administers(ctx discord.Context, guildID string) bool {
+	switch s {
+	case globalAdmin:
+		return true
+	case guildAdmin:
+		return guildID != "" && guildID == ctx.Interaction.GuildID
+	default:
+		return false
+	}
+}
+
+// isAdmin reports whether the user administers the guild the interaction came from, either globally or through the guild's admin roles.
+func isAdmin(ctx discord.Context, sess db.Session, access config.Access) bool {
+	return getAdminScope(ctx, sess, access) != notAdmin
+}
+
+// isGuildMember reports whether a user given as a command option is a member of the interaction's guild.
+func isGuildMember(ctx discord.Context, userID string) bool {
+	if ctx.Interaction.GuildID == "" {
+		return false
+	}
+	// Discord only resolves members of the guild the command was used in
+	resolved := ctx.Interaction.ApplicationCommandData().Resolved
+	if resolved == nil {
+		return false
+	}
+	_, ok := resolved.Members[userID]
+	return ok
+}
@@ -3,28 +3,28 @@ package commands
 import (
 	"errors"
 	"fmt"
-	"strings"
 
-	"github.com/bobcob7/polly-bot/internal/models"
+	"github.com/bobcob7/polly-bot/internal/config"
+	"github.com/bobcob7/polly-bot/internal/downloads"
 	"github.com/bobcob7/polly-bot/internal/torrent"
 	"github.com/bobcob7/polly-bot/pkg/discord"
-	"github.com/bobcob7/transmission-rpc"
 	"github.com/bwmarrin/discordgo"
-	"github.com/google/uuid"
 	"github.com/upper/db/v4"
 	"go.uber.org/zap"
 )
 
 type AddCommand struct {
+	adder     *downloads.Adder
 	sess      db.Session
-	tx        *transmission.Client
+	access    config.Access
 	customIDs map[string]struct{}
 }
 
-func NewAddCommand(sess db.Session, tx *transmission.Client) *AddCommand {
+func NewAddCommand(adder *downloads.Adder, sess db.Session, access config.Access) *AddCommand {
 	return &AddCommand{
+		adder:     adder,
 		sess:      sess,
-		tx:        tx,
+		access:    access,
 		customIDs: make(map[string]struct{}),
 	}
 }
@@ -37,41 +37,38 @@ func (p *AddCommand) Command() *discordgo.ApplicationCommand {
 	return &discordgo.ApplicationCommand{
 		Name:        p.Name(),
 		Description: "Add a new torrent",
-		Options: []*discordgo.ApplicationCommandOption{
-			{
-				Type:        discordgo.ApplicationCommandOptionString,
-				Name:        "magnet",
-				Description: "Magnet link to add",
-				Required:    true,
-			},
-		},
+		Options:     discord.CommandOptions(addOptions{}),
 	}
 }
 
+type addOptions struct {
+	Magnet string `option:"magnet" description:"Magnet link to add" required:"true"`
+}
+
 func (p *AddCommand) HasCustomID(customID string) bool {
 	_, ok := p.customIDs[customID]
 	return ok
 }
 
-var validCategories = []string{
-	"MOVIE",
-	"TV SHOW",
-	"MUSIC",
-	"AUDIOBOOK",
-	"SOFTWARE",
-}
-
 var errInvalidMagnetLink = errors.New("invalid magnet link")
 
 func (p *AddCommand) Handle(ctx discord.Context) error {
-	// Get finished input
-	magnetURI := ctx.Interaction.ApplicationCommandData().Options[0].StringValue()
+	var options addOptions
+	if err := ctx.Options().Decode(&options); err != nil {
+		return err
+	}
+	magnetURI := options.Magnet
 	// Get display name from URI
 	displayName, err := torrent.MagnetURIDisplayName(magnetURI)
 	if err != nil {
 		return errInvalidMagnetLink
 	}
 	logger := ctx.Logger().With(zap.String("displayName", displayName))
+	// Suggest a readable name and category from the release name
+	release := torrent.ParseRelease(displayName)
+	if name := release.FriendlyName(); name != "" {
+		displayName = name
+	}
 
 	customID := ctx.Interaction.ID
 	p.customIDs[customID] = struct{}{}
@@ -81,7 +78,7 @@ func (p *AddCommand) Handle(ctx discord.Context) error {
 		displayName = displayName[:99]
 	}
 
-	if err := ctx.Session.InteractionRespond(ctx.Interaction, &discordgo.InteractionResponse{
+	if err := ctx.Respond(&discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseModal,
 		Data: &discordgo.InteractionResponseData{
 			Title:    "Add torrent dialog",
@@ -107,6 +104,7 @@ func (p *AddCommand) Handle(ctx discord.Context) error {
 							Label:       "Category",
 							Placeholder: `"Movie", "TV Show", "Music", "Audiobook", "Book", "Software"`,
 							Style:       discordgo.TextInputShort,
+							Value:       release.Category(),
 							Required:    false,
 						},
 					},
@@ -136,80 +134,47 @@ func (p *AddCommand) HandleModal(ctx discord.Context, id string) error {
 		return discord.ErrNotFound
 	}
 	defer delete(p.customIDs, id)
-	// Add torent with link and friendly name
-	data := ctx.Interaction.ModalSubmitData()
-	logger := ctx.Logger()
-	nameInput, ok := data.Components[0].(*discordgo.ActionsRow).Components[0].(*discordgo.TextInput)
-	if !ok {
-		return errFailedTypeAssertion
+	// Adding talks to transmission and the db, which can take longer than Discord waits for a response
+	if err := ctx.Defer(true); err != nil {
+		return fmt.Errorf("failed to defer response: %w", err)
 	}
-	name := nameInput.Value
-	rawCategoryInput, ok := data.Components[1].(*discordgo.ActionsRow).Components[0].(*discordgo.TextInput)
-	if !ok {
-		return errFailedTypeAssertion
-	}
-	rawCategory := rawCategoryInput.Value
-	linkInput, ok := data.Components[2].(*discordgo.ActionsRow).Components[0].(*discordgo.TextInput)
-	if !ok {
-		return errFailedTypeAssertion
-	}
-	link := linkInput.Value
-
-	meta := &models.TorrentMetadata{
-		FriendlyName: name,
-	}
-	// Validate that categories are correct
-	var opts transmission.AddMagnetLinkOption
-	if rawCategory != "" {
-		var found bool
-		category := strings.ToUpper(strings.TrimSpace(rawCategory))
-		for _, knownCategory := range validCategories {
-			if category == knownCategory {
-				found = true
-				break
-			}
-		}
-		if !found {
-			return unexpectedCategoryError{rawCategory}
-		}
-		opts = transmission.DownloadSubDirOption(strings.ToLower(category))
-		meta.Categories = []string{category}
+	// Add torent with link and friendly name
+	name, err := ctx.ModalValue("name")
+	if err != nil {
+		return err
 	}
-	// Adding magnet link
-	torrentID, err := p.tx.AddMagnetLink(ctx, link, opts)
+	rawCategory, err := ctx.ModalValue("category")
 	if err != nil {
-		return fmt.Errorf("failed to add magnet link: %w", err)
+		return err
 	}
-	// Scrape new torrent
-	torrents, err := p.tx.GetTorrents(ctx, torrentID)
+	link, err := ctx.ModalValue("link")
 	if err != nil {
-		return fmt.Errorf("failed to get torrents from db: %w", err)
+		return err
 	}
-	logger.Debug("scraped torrent from transmission", zap.Int("id", torrentID))
-	if len(torrents) != 1 {
-		return unexpectedNumberOfTorrentsError{
-			want: 1,
-			got:  len(torrents),
+
+	req := downloads.Request{
+		MagnetLink:   link,
+		FriendlyName: name,
+		Category:     rawCategory,
+		RecipientID:  ctx.UserID(),
+		ChannelID:    ctx.ChannelID(),
+		RequestedBy:  ctx.UserID(),
+		Roles:        ctx.MemberRoles(),
+		GuildID:      ctx.Interaction.GuildID,
+	}
+	content := "Thank you sharing"
+	if p.access.ModerationChannelID != "" && !isAdmin(ctx, p.sess, p.access) {
+		if err := p.request(ctx, req); err != nil {
+			return err
 		}
+		content = "Thank you sharing, an admin will review your request"
+	} else if _, err := p.adder.Add(ctx, req); err != nil {
+		return fmt.Errorf("failed to add torrent: %w", err)
 	}
-	newTorrent := models.FromTransmission(torrents[0])
-	newTorrent.TorrentMetadata = meta
-	if _, err := newTorrent.Set(ctx, p.sess); err != nil {
-		return fmt.Errorf("failed to set in db: %w", err)
-	}
-	notification := models.TorrentNotification{
-		ID:          uuid.NewString(),
-		TorrentID:   newTorrent.ID,
-		RecipientID: ctx.UserID(),
-		ChannelID:   ctx.ChannelID(),
-	}
-	if err := notification.Create(ctx, p.sess); err != nil {
-		return fmt.Errorf("failed to create notification: %w", err)
-	}
-	if err := ctx.InteractionRespond(ctx.Interaction, &discordgo.InteractionResponse{
+	if err := ctx.Respond(&discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
-			Content: "Thank you sharing",
+			Content: content,
 			Flags:   discordgo.MessageFlagsEphemeral,
 		},
 	}); err != nil {
@@ -0,0 +1,150 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bobcob7/polly-bot/internal/config"
+	"github.com/bobcob7/polly-bot/internal/models"
+	"github.com/bobcob7/polly-bot/pkg/discord"
+	"github.com/bwmarrin/discordgo"
+	"github.com/upper/db/v4"
+)
+
+// APIKeyCommand manages the API keys RPC callers authenticate with.
+// Keys aren't limited to a guild, so only global admins can manage them.
+type APIKeyCommand struct {
+	sess   db.Session
+	access config.Access
+}
+
+func NewAPIKeyCommand(sess db.Session, access config.Access) *APIKeyCommand {
+	return &APIKeyCommand{
+		sess:   sess,
+		access: access,
+	}
+}
+
+func (p *APIKeyCommand) Name() string {
+	return "apikey"
+}
+
+func (p *APIKeyCommand) Command() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        p.Name(),
+		Description: "Manage API keys for the RPC server",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "create",
+				Description: "Create an API key, it is only shown once",
+				Options:     discord.CommandOptions(apiKeyCreateOptions{}),
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "list",
+				Description: "List API keys",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "revoke",
+				Description: "Revoke an API key",
+				Options:     discord.CommandOptions(apiKeyRevokeOptions{}),
+			},
+		},
+	}
+}
+
+type apiKeyCreateOptions struct {
+	Name      string `option:"name" description:"What the key is used for" required:"true"`
+	Scope     string `option:"scope" description:"Read only, or write and admin which include the scopes before them" required:"true" choices:"read|write|admin"`
+	RateLimit int    `option:"rate-limit" description:"RPCs per minute, defaults to the configured limit" min:"0"`
+}
+
+type apiKeyRevokeOptions struct {
+	ID string `option:"id" description:"ID of the key" required:"true"`
+}
+
+func (p *APIKeyCommand) Handle(ctx discord.Context) error {
+	if !p.access.IsAdmin(ctx.UserID(), ctx.MemberRoles()) {
+		return errAdminRequired
+	}
+	options := ctx.Options()
+	var content string
+	var err error
+	switch options.SubCommand() {
+	case "create":
+		content, err = p.create(ctx, options)
+	case "list":
+		content, err = p.list(ctx)
+	case "revoke":
+		content, err = p.revoke(ctx, options)
+	default:
+		return unexpectedSubCommandError{options.SubCommand()}
+	}
+	if err != nil {
+		return err
+	}
+	if err := ctx.Respond(&discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags:   discordgo.MessageFlagsEphemeral,
+			Content: content,
+		},
+	}); err != nil {
+		return failedResponseInteractionError{err}
+	}
+	return nil
+}
+
+func (p *APIKeyCommand) create(ctx discord.Context, rawOptions discord.Options) (string, error) {
+	var options apiKeyCreateOptions
+	if err := rawOptions.Decode(&options); err != nil {
+		return "", err
+	}
+	key, token, err := models.NewAPIKey(strings.TrimSpace(options.Name), []string{options.Scope}, options.RateLimit, ctx.UserID())
+	if err != nil {
+		return "", err
+	}
+	if err := key.Create(ctx, p.sess); err != nil {
+		return "", fmt.Errorf("failed to create api key: %w", err)
+	}
+	return fmt.Sprintf("Created API key %s, it won't be shown again:\n`%s`", key.ID, token), nil
+}
+
+func (p *APIKeyCommand) list(ctx discord.Context) (string, error) {
+	keys, err := models.GetAPIKeys(ctx, p.sess)
+	if err != nil {
+		return "", fmt.Errorf("failed to get api keys: %w", err)
+	}
+	if len(keys) == 0 {
+		return "No API keys found", nil
+	}
+	content := make([]string, 0, len(keys))
+	for _, key := range keys {
+		content = append(content, key.String())
+	}
+	return strings.Join(content, "\n"), nil
+}
+
+func (p *APIKeyCommand) revoke(ctx discord.Context, rawOptions discord.Options) (string, error) {
+	var options apiKeyRevokeOptions
+	if err := rawOptions.Decode(&options); err != nil {
+		return "", err
+	}
+	key, err := models.GetAPIKey(ctx, p.sess, strings.TrimSpace(options.ID))
+	if err != nil {
+		if errors.Is(err, db.ErrNoMoreRows) {
+			return fmt.Sprintf("API key %s doesn't exist", options.ID), nil
+		}
+		return "", err
+	}
+	if key.Revoked() {
+		return fmt.Sprintf("API key %s is already revoked", key.ID), nil
+	}
+	if err := key.Revoke(ctx, p.sess); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Revoked API key %s", key.ID), nil
+}
@@ -0,0 +1,84 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/bobcob7/polly-bot/internal/config"
+	"github.com/bobcob7/polly-bot/internal/models"
+	"github.com/bobcob7/polly-bot/pkg/discord"
+	"github.com/bwmarrin/discordgo"
+	"github.com/upper/db/v4"
+)
+
+type AuditCommand struct {
+	sess   db.Session
+	access config.Access
+}
+
+func NewAuditCommand(sess db.Session, access config.Access) *AuditCommand {
+	return &AuditCommand{
+		sess:   sess,
+		access: access,
+	}
+}
+
+func (p *AuditCommand) Name() string {
+	return "audit"
+}
+
+func (p *AuditCommand) Command() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        p.Name(),
+		Description: "Shows recent bot actions",
+		Options:     discord.CommandOptions(auditOptions{}),
+	}
+}
+
+type auditOptions struct {
+	User   discord.UserID `option:"user" description:"Only show actions by this user"`
+	Action string         `option:"action" description:"Only show this command or RPC procedure"`
+	Limit  int            `option:"limit" description:"Number of events to show" default:"20" min:"1" max:"50"`
+}
+
+func (p *AuditCommand) Handle(ctx discord.Context) error {
+	scope := getAdminScope(ctx, p.sess, p.access)
+	if scope == notAdmin {
+		return errAdminRequired
+	}
+	var options auditOptions
+	if err := ctx.Options().Decode(&options); err != nil {
+		return err
+	}
+	cond := db.Cond{}
+	// Guild admins only see what happened in their guild
+	if scope != globalAdmin {
+		cond["guild_id"] = ctx.Interaction.GuildID
+	}
+	if options.User != "" {
+		cond["user_id"] = string(options.User)
+	}
+	if options.Action != "" {
+		cond["action"] = options.Action
+	}
+	events, err := models.GetAuditEvents(ctx, p.sess, cond, options.Limit)
+	if err != nil {
+		return fmt.Errorf("failed to get audit events: %w", err)
+	}
+	content := make([]string, 0, len(events))
+	for _, event := range events {
+		content = append(content, event.String())
+	}
+	if len(content) == 0 {
+		content = append(content, "No audit events found")
+	}
+	if err := ctx.Respond(&discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags:   discordgo.MessageFlagsEphemeral,
+			Content: joinLines(content),
+		},
+	}); err != nil {
+		return failedResponseInteractionError{err}
+	}
+	return nil
+}
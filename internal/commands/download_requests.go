@@ -0,0 +1,145 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bobcob7/polly-bot/internal/downloads"
+	"github.com/bobcob7/polly-bot/internal/models"
+	"github.com/bobcob7/polly-bot/pkg/discord"
+	"github.com/bwmarrin/discordgo"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	requestComponentPrefix = "request:"
+	requestApproveAction   = "approve"
+	requestDenyAction      = "deny"
+)
+
+// request posts the torrent to the moderation channel instead of adding it.
+func (p *AddCommand) request(ctx discord.Context, req downloads.Request) error {
+	if err := p.adder.Check(ctx, req); err != nil {
+		return fmt.Errorf("failed to request torrent: %w", err)
+	}
+	request := &models.DownloadRequest{
+		ID:             uuid.NewString(),
+		MagnetLink:     req.MagnetLink,
+		FriendlyName:   req.FriendlyName,
+		Category:       req.Category,
+		RequestedBy:    req.RequestedBy,
+		RequesterRoles: strings.Join(req.Roles, ","),
+		ChannelID:      req.ChannelID,
+		GuildID:        req.GuildID,
+	}
+	if err := request.Create(ctx, p.sess); err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if _, err := ctx.Session.ChannelMessageSendComplex(p.access.ModerationChannelID, requestMessage(request)); err != nil {
+		// Nobody could approve it without the message
+		if deleteErr := request.Delete(ctx, p.sess); deleteErr != nil {
+			ctx.Logger().Error("failed to delete unposted request", zap.Error(deleteErr))
+		}
+		return fmt.Errorf("failed to post request: %w", err)
+	}
+	return nil
+}
+
+func requestMessage(request *models.DownloadRequest) *discordgo.MessageSend {
+	content := fmt.Sprintf("<@%s> requested %s", request.RequestedBy, request.FriendlyName)
+	if request.Category != "" {
+		content += fmt.Sprintf(" as %s", request.Category)
+	}
+	return &discordgo.MessageSend{
+		Content: content,
+		Components: []discordgo.MessageComponent{
+			discordgo.ActionsRow{
+				Components: []discordgo.MessageComponent{
+					discordgo.Button{
+						Label:    "Approve",
+						Style:    discordgo.SuccessButton,
+						CustomID: requestComponentPrefix + requestApproveAction + ":" + request.ID,
+					},
+					discordgo.Button{
+						Label:    "Deny",
+						Style:    discordgo.DangerButton,
+						CustomID: requestComponentPrefix + requestDenyAction + ":" + request.ID,
+					},
+				},
+			},
+		},
+	}
+}
+
+func (p *AddCommand) HasComponentID(id string) bool {
+	return strings.HasPrefix(id, requestComponentPrefix)
+}
+
+// HandleComponent approves or denies a request, adding approved torrents on behalf of the requester.
+func (p *AddCommand) HandleComponent(ctx discord.Context, id string) error {
+	action, requestID, ok := strings.Cut(strings.TrimPrefix(id, requestComponentPrefix), ":")
+	if !ok {
+		return discord.ErrNotFound
+	}
+	request := &models.DownloadRequest{ID: requestID}
+	if err := request.Get(ctx, p.sess); err != nil {
+		return fmt.Errorf("failed to get request: %w", err)
+	}
+	// Every guild's requests share the moderation channel, guild admins can only decide their own
+	if !getAdminScope(ctx, p.sess, p.access).administers(ctx, request.GuildID) {
+		return errAdminRequired
+	}
+	status := models.DownloadRequestDenied
+	if action == requestApproveAction {
+		status = models.DownloadRequestApproved
+	} else if action != requestDenyAction {
+		return discord.ErrNotFound
+	}
+	decided, err := request.Decide(ctx, p.sess, status, ctx.UserID())
+	if err != nil {
+		return fmt.Errorf("failed to decide request: %w", err)
+	}
+	if !decided {
+		return p.updateRequestMessage(ctx, fmt.Sprintf("%s was already %s", request.FriendlyName, request.Status))
+	}
+	if status == models.DownloadRequestApproved {
+		torrent, err := p.adder.Add(ctx, downloads.Request{
+			MagnetLink:   request.MagnetLink,
+			FriendlyName: request.FriendlyName,
+			Category:     request.Category,
+			RecipientID:  request.RequestedBy,
+			ChannelID:    request.ChannelID,
+			RequestedBy:  request.RequestedBy,
+			Roles:        request.Roles(),
+			GuildID:      request.GuildID,
+		})
+		if err != nil {
+			if reopenErr := request.Reopen(ctx, p.sess); reopenErr != nil {
+				ctx.Logger().Error("failed to reopen request", zap.Error(reopenErr))
+			}
+			return fmt.Errorf("failed to add torrent: %w", err)
+		}
+		if err := request.SetTorrent(ctx, p.sess, torrent.ID); err != nil {
+			return fmt.Errorf("failed to link request: %w", err)
+		}
+	}
+	ctx.Logger().Info("decided download request", zap.String("requestID", request.ID), zap.String("status", status))
+	if err := ctx.PrivateMessenger.SendMessage(ctx, request.RequestedBy, fmt.Sprintf("Your request for %s was %s", request.FriendlyName, status)); err != nil {
+		ctx.Logger().Error("failed to notify requester", zap.Error(err))
+	}
+	return p.updateRequestMessage(ctx, fmt.Sprintf("<@%s> %s %s requested by <@%s>", ctx.UserID(), status, request.FriendlyName, request.RequestedBy))
+}
+
+func (p *AddCommand) updateRequestMessage(ctx discord.Context, content string) error {
+	if err := ctx.Respond(&discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    content,
+			Components: []discordgo.MessageComponent{},
+		},
+	}); err != nil {
+		return failedResponseInteractionError{err}
+	}
+	return nil
+}
@@ -25,6 +25,7 @@ type Echo struct {
 func (p *Echo) Run(ctx context.Context, s *discordgo.Session) error {
 	p.jobs = make(map[string]Job)
 	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
 	for {
 		select {
 		case <-ctx.Done():
@@ -35,9 +36,8 @@ func (p *Echo) Run(ctx context.Context, s *discordgo.Session) error {
 				if job.ttl.Before(time.Now()) {
 					zap.L().Debug("echo finished", zap.String("id", id))
 					delete(p.jobs, id)
-					_, err := s.ChannelMessageSend(job.channelID, fmt.Sprintf("Echo %s", id))
-					if err != nil {
-						return fmt.Errorf("failed to send channel response: %w", err)
+					if _, err := s.ChannelMessageSend(job.channelID, fmt.Sprintf("Echo %s", id)); err != nil {
+						zap.L().Error("failed to send channel response", zap.Error(err))
 					}
 				}
 			}
@@ -50,23 +50,28 @@ func (p *Echo) Name() string {
 	return "echo"
 }
 
+func (p *Echo) Ephemeral() bool {
+	return false
+}
+
 func (p *Echo) Command() *discordgo.ApplicationCommand {
 	return &discordgo.ApplicationCommand{
 		Name:        p.Name(),
 		Description: "Echo returns immediately, then makes an announcement after a specified delay",
-		Options: []*discordgo.ApplicationCommandOption{
-			{
-				Type:        discordgo.ApplicationCommandOptionString,
-				Name:        "delay",
-				Description: "Delay to wait until the announcement is made",
-				Required:    true,
-			},
-		},
+		Options:     discord.CommandOptions(echoOptions{}),
 	}
 }
 
+type echoOptions struct {
+	Delay string `option:"delay" description:"Delay to wait until the announcement is made" required:"true"`
+}
+
 func (p *Echo) Handle(ctx discord.Context) error {
-	input := ctx.Interaction.ApplicationCommandData().Options[0].StringValue()
+	var options echoOptions
+	if err := ctx.Options().Decode(&options); err != nil {
+		return err
+	}
+	input := options.Delay
 	delay, err := time.ParseDuration(input)
 	if err != nil {
 		return fmt.Errorf("failed to parse input duration %q: %w", input, err)
@@ -80,7 +85,7 @@ func (p *Echo) Handle(ctx discord.Context) error {
 		channelID: ctx.Interaction.ChannelID,
 	}
 	p.lock.Unlock()
-	err = ctx.Session.InteractionRespond(ctx.Interaction, &discordgo.InteractionResponse{
+	err = ctx.Respond(&discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
 			Title:   "...",
@@ -1,7 +1,6 @@
 package commands
 
 import (
-	"errors"
 	"fmt"
 )
 
@@ -17,21 +16,10 @@ func (f failedResponseInteractionError) Error() string {
 	return fmt.Sprintf("failed to send response interation: %v", f.err)
 }
 
-type unexpectedCategoryError struct {
-	category string
+type unexpectedSubCommandError struct {
+	name string
 }
 
-func (u unexpectedCategoryError) Error() string {
-	return fmt.Sprintf("unknown category: %q", u.category)
+func (u unexpectedSubCommandError) Error() string {
+	return fmt.Sprintf("unknown sub command: %q", u.name)
 }
-
-type unexpectedNumberOfTorrentsError struct {
-	want int
-	got  int
-}
-
-func (u unexpectedNumberOfTorrentsError) Error() string {
-	return fmt.Sprintf("scraped %d torrents intead of %d", u.got, u.want)
-}
-
-var errFailedTypeAssertion = errors.New("failed type assertion")
@@ -41,17 +41,16 @@ func (p *GetAllCommand) Command() *discordgo.ApplicationCommand {
 
 func (p *GetAllCommand) Handle(ctx discord.Context) error {
 	// Get finished input
-	args := make([]interface{}, 0)
-	if len(ctx.Interaction.ApplicationCommandData().Options) != 0 {
-		finished := ctx.Interaction.ApplicationCommandData().Options[0].BoolValue()
-		if finished {
-			args = append(args, "completed_at IS NOT NULL")
+	cond := models.InGuild(ctx.Interaction.GuildID)
+	if options := ctx.Options(); options.Has("finished") {
+		if options.Bool("finished", false) {
+			cond["completed_at"] = db.IsNotNull()
 		} else {
-			args = append(args, "completed_at IS NULL")
+			cond["completed_at"] = db.IsNull()
 		}
 	}
 
-	torrents, err := models.GetTorrents(ctx, p.sess, args...)
+	torrents, err := models.GetTorrents(ctx, p.sess, statusLimit, cond)
 	if err != nil {
 		return fmt.Errorf("failed to get torrents from db: %w", err)
 	}
@@ -65,7 +64,7 @@ func (p *GetAllCommand) Handle(ctx discord.Context) error {
 		content = append(content, "No torrents found")
 	}
 
-	err = ctx.Session.InteractionRespond(ctx.Interaction, &discordgo.InteractionResponse{
+	err = ctx.Respond(&discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
 			Title:   title,
@@ -0,0 +1,105 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/bobcob7/polly-bot/internal/config"
+	"github.com/bobcob7/polly-bot/internal/downloads"
+	"github.com/bobcob7/polly-bot/internal/models"
+	"github.com/bobcob7/polly-bot/pkg/discord"
+	"github.com/bwmarrin/discordgo"
+	"github.com/upper/db/v4"
+)
+
+const historyLimit = 15
+
+type HistoryCommand struct {
+	sess   db.Session
+	access config.Access
+}
+
+func NewHistoryCommand(sess db.Session, access config.Access) *HistoryCommand {
+	return &HistoryCommand{
+		sess:   sess,
+		access: access,
+	}
+}
+
+func (p *HistoryCommand) Name() string {
+	return "history"
+}
+
+func (p *HistoryCommand) Command() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        p.Name(),
+		Description: "Shows the torrents a user requested",
+		Options:     discord.CommandOptions(historyOptions{}),
+	}
+}
+
+type historyOptions struct {
+	User discord.UserID `option:"user" description:"User to show, defaults to you"`
+}
+
+func (p *HistoryCommand) Handle(ctx discord.Context) error {
+	var options historyOptions
+	if err := ctx.Options().Decode(&options); err != nil {
+		return err
+	}
+	userID := ctx.UserID()
+	if options.User != "" && string(options.User) != userID {
+		if !isAdmin(ctx, p.sess, p.access) {
+			return errAdminRequired
+		}
+		userID = string(options.User)
+	}
+	torrents, err := models.GetRequestedTorrents(ctx, p.sess, userID, ctx.Interaction.GuildID, historyLimit)
+	if err != nil {
+		return fmt.Errorf("failed to get requested torrents: %w", err)
+	}
+	requests, err := models.GetUserDownloadRequests(ctx, p.sess, userID, ctx.Interaction.GuildID, historyLimit)
+	if err != nil {
+		return fmt.Errorf("failed to get download requests: %w", err)
+	}
+	content := []string{fmt.Sprintf("History of <@%s>", userID)}
+	for _, torrent := range torrents {
+		content = append(content, fmt.Sprintf("%s %s: %s", torrent.CreatedAt.Format("2006-01-02"), torrent.NameString(), torrentOutcome(torrent)))
+	}
+	// Approved requests already show up as torrents
+	for _, request := range requests {
+		if request.Status == models.DownloadRequestApproved {
+			continue
+		}
+		content = append(content, fmt.Sprintf("%s %s: request %s", request.CreatedAt.Format("2006-01-02"), request.FriendlyName, request.Status))
+	}
+	if len(content) == 1 {
+		content = append(content, "Nothing requested yet")
+	}
+	if err := ctx.Respond(&discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags:   discordgo.MessageFlagsEphemeral,
+			Content: joinLines(content),
+		},
+	}); err != nil {
+		return failedResponseInteractionError{err}
+	}
+	return nil
+}
+
+// torrentOutcome describes how a requested torrent ended up, or how far along it is.
+func torrentOutcome(torrent *models.Torrent) string {
+	if value, ok := torrent.Label(models.DiskGuardLabel); ok && value != downloads.DiskGuardOK {
+		return "disk guard " + value
+	}
+	if torrent.DeletedAt != nil {
+		if value, ok := torrent.Label(models.SeedingPolicyLabel); ok {
+			return "removed, " + value
+		}
+		return "removed"
+	}
+	if torrent.CompletedAt != nil {
+		return "completed " + torrent.CompletedAt.Format("2006-01-02")
+	}
+	return torrent.String()
+}
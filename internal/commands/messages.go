@@ -0,0 +1,38 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxMessageLength is the most characters Discord allows in a message.
+const maxMessageLength = 2000
+
+// joinLines joins as many whole lines as fit in a message,
+// ending with how many were left out if they don't all fit.
+func joinLines(lines []string) string {
+	var b strings.Builder
+	for i, line := range lines {
+		room := maxMessageLength - b.Len()
+		if i > 0 {
+			room--
+		}
+		// Leave room to say how many lines were left out, unless this is the last one
+		if rest := len(lines) - i - 1; rest > 0 {
+			room -= len(omittedLines(rest)) + 1
+		}
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		if len(line) > room {
+			b.WriteString(omittedLines(len(lines) - i))
+			break
+		}
+		b.WriteString(line)
+	}
+	return b.String()
+}
+
+func omittedLines(count int) string {
+	return fmt.Sprintf("…and %d more", count)
+}
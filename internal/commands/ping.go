@@ -17,6 +17,10 @@ func (p *Ping) Name() string {
 	return "ping"
 }
 
+func (p *Ping) Ephemeral() bool {
+	return false
+}
+
 func (p *Ping) Command() *discordgo.ApplicationCommand {
 	return &discordgo.ApplicationCommand{
 		Name:        p.Name(),
@@ -29,7 +33,7 @@ func (p *Ping) Handle(ctx discord.Context) error {
 	p.count++
 	currentCount := p.count
 	p.lock.Unlock()
-	if err := ctx.Session.InteractionRespond(ctx.Interaction, &discordgo.InteractionResponse{
+	if err := ctx.Respond(&discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
 			Title:   fmt.Sprintf("Ping#%d", currentCount),
@@ -0,0 +1,216 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/bobcob7/polly-bot/internal/config"
+	"github.com/bobcob7/polly-bot/internal/downloads"
+	"github.com/bobcob7/polly-bot/internal/models"
+	"github.com/bobcob7/polly-bot/pkg/discord"
+	"github.com/bwmarrin/discordgo"
+	"github.com/upper/db/v4"
+)
+
+const gibibyte = 1024 * megabyte
+
+var (
+	errAdminRequired       = errors.New("only admins can do that")
+	errGlobalAdminRequired = errors.New("only bot admins can change quotas, they apply in every server")
+)
+
+type QuotaCommand struct {
+	sess   db.Session
+	quotas *downloads.Quotas
+	access config.Access
+}
+
+func NewQuotaCommand(sess db.Session, quotas *downloads.Quotas, access config.Access) *QuotaCommand {
+	return &QuotaCommand{
+		sess:   sess,
+		quotas: quotas,
+		access: access,
+	}
+}
+
+func (p *QuotaCommand) Name() string {
+	return "quota"
+}
+
+func (p *QuotaCommand) Command() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        p.Name(),
+		Description: "Shows and manages download quotas",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "show",
+				Description: "Show quota usage",
+				Options:     discord.CommandOptions(quotaShowOptions{}),
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "set",
+				Description: "Override a user's quota, zero is unlimited and blocked allows nothing",
+				Options:     discord.CommandOptions(quotaSetOptions{}),
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "reset",
+				Description: "Remove a user's quota override",
+				Options:     discord.CommandOptions(quotaResetOptions{}),
+			},
+		},
+	}
+}
+
+type quotaShowOptions struct {
+	User discord.UserID `option:"user" description:"User to show, defaults to you"`
+}
+
+type quotaSetOptions struct {
+	User      discord.UserID `option:"user" description:"User whose quota to change" required:"true"`
+	MaxActive int            `option:"max-active" description:"Maximum unfinished downloads" required:"true" min:"0"`
+	DailyGiB  uint64         `option:"daily-gib" description:"GiB per day" required:"true" min:"0"`
+	WeeklyGiB uint64         `option:"weekly-gib" description:"GiB per week" required:"true" min:"0"`
+	Blocked   bool           `option:"blocked" description:"Block the user from adding downloads, ignoring the limits"`
+}
+
+type quotaResetOptions struct {
+	User discord.UserID `option:"user" description:"User whose quota to change" required:"true"`
+}
+
+func (p *QuotaCommand) Handle(ctx discord.Context) error {
+	options := ctx.Options()
+	var content string
+	var err error
+	switch options.SubCommand() {
+	case "show":
+		content, err = p.show(ctx, options)
+	case "set":
+		content, err = p.set(ctx, options)
+	case "reset":
+		content, err = p.reset(ctx, options)
+	default:
+		return unexpectedSubCommandError{options.SubCommand()}
+	}
+	if err != nil {
+		return err
+	}
+	if err := ctx.Respond(&discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags:   discordgo.MessageFlagsEphemeral,
+			Content: content,
+		},
+	}); err != nil {
+		return failedResponseInteractionError{err}
+	}
+	return nil
+}
+
+func (p *QuotaCommand) show(ctx discord.Context, rawOptions discord.Options) (string, error) {
+	var options quotaShowOptions
+	if err := rawOptions.Decode(&options); err != nil {
+		return "", err
+	}
+	userID, roles := ctx.UserID(), ctx.MemberRoles()
+	if options.User != "" && string(options.User) != userID {
+		if !p.administers(ctx, string(options.User)) {
+			return "", errAdminRequired
+		}
+		userID, roles = string(options.User), nil
+		if resolved := ctx.Interaction.ApplicationCommandData().Resolved; resolved != nil {
+			if member, ok := resolved.Members[userID]; ok {
+				roles = member.Roles
+			}
+		}
+	}
+	limit, limited, err := p.quotas.Limit(ctx, userID, roles)
+	if err != nil {
+		return "", fmt.Errorf("failed to get quota: %w", err)
+	}
+	if !limited {
+		return fmt.Sprintf("<@%s> is an admin without a quota", userID), nil
+	}
+	if limit.Blocked {
+		return fmt.Sprintf("<@%s> is blocked from adding downloads", userID), nil
+	}
+	usage, err := models.GetUserUsage(ctx, p.sess, userID, time.Now())
+	if err != nil {
+		return "", fmt.Errorf("failed to get quota usage: %w", err)
+	}
+	maxActive := "unlimited"
+	if limit.MaxActive != 0 {
+		maxActive = strconv.Itoa(limit.MaxActive)
+	}
+	return fmt.Sprintf("Quota of <@%s>\nActive downloads: %d of %s\nToday: %s of %s\nThis week: %s of %s",
+		userID,
+		usage.Active, maxActive,
+		formatBytes(usage.DailyBytes), formatByteLimit(limit.DailyBytes),
+		formatBytes(usage.WeeklyBytes), formatByteLimit(limit.WeeklyBytes),
+	), nil
+}
+
+func formatByteLimit(limit uint64) string {
+	if limit == 0 {
+		return "unlimited"
+	}
+	return formatBytes(limit)
+}
+
+func (p *QuotaCommand) set(ctx discord.Context, rawOptions discord.Options) (string, error) {
+	var options quotaSetOptions
+	if err := rawOptions.Decode(&options); err != nil {
+		return "", err
+	}
+	if getAdminScope(ctx, p.sess, p.access) != globalAdmin {
+		return "", errGlobalAdminRequired
+	}
+	quota := &models.UserQuota{
+		UserID:      string(options.User),
+		MaxActive:   options.MaxActive,
+		DailyBytes:  options.DailyGiB * gibibyte,
+		WeeklyBytes: options.WeeklyGiB * gibibyte,
+		Blocked:     options.Blocked,
+		UpdatedBy:   ctx.UserID(),
+	}
+	if err := quota.Set(ctx, p.sess); err != nil {
+		return "", fmt.Errorf("failed to set quota: %w", err)
+	}
+	if quota.Blocked {
+		return fmt.Sprintf("Blocked <@%s> from adding downloads", quota.UserID), nil
+	}
+	return fmt.Sprintf("Set the quota of <@%s>", quota.UserID), nil
+}
+
+func (p *QuotaCommand) reset(ctx discord.Context, rawOptions discord.Options) (string, error) {
+	var options quotaResetOptions
+	if err := rawOptions.Decode(&options); err != nil {
+		return "", err
+	}
+	if getAdminScope(ctx, p.sess, p.access) != globalAdmin {
+		return "", errGlobalAdminRequired
+	}
+	userID := string(options.User)
+	if err := models.DeleteUserQuota(ctx, p.sess, userID); err != nil {
+		return "", fmt.Errorf("failed to reset quota: %w", err)
+	}
+	return fmt.Sprintf("Reset the quota of <@%s>", userID), nil
+}
+
+// administers reports whether the user can see another user's quota,
+// guild admins can only see members of their guild.
+// Quotas apply in every guild, so only global admins can change them.
+func (p *QuotaCommand) administers(ctx discord.Context, userID string) bool {
+	switch getAdminScope(ctx, p.sess, p.access) {
+	case globalAdmin:
+		return true
+	case guildAdmin:
+		return isGuildMember(ctx, userID)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,210 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bobcob7/polly-bot/internal/config"
+	"github.com/bobcob7/polly-bot/internal/downloads"
+	"github.com/bobcob7/polly-bot/internal/models"
+	"github.com/bobcob7/polly-bot/internal/rss"
+	"github.com/bobcob7/polly-bot/pkg/discord"
+	"github.com/bwmarrin/discordgo"
+	"github.com/google/uuid"
+	"github.com/upper/db/v4"
+)
+
+const megabyte = 1024 * 1024
+
+var errUnknownRule = errors.New("no rule with that ID")
+
+type RSSCommand struct {
+	sess   db.Session
+	access config.Access
+}
+
+func NewRSSCommand(sess db.Session, access config.Access) *RSSCommand {
+	return &RSSCommand{
+		sess:   sess,
+		access: access,
+	}
+}
+
+func (p *RSSCommand) Name() string {
+	return "rss"
+}
+
+func (p *RSSCommand) Command() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        p.Name(),
+		Description: "Manage RSS feeds which automatically add matching torrents",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "add",
+				Description: "Add a rule for a feed",
+				Options:     discord.CommandOptions(rssAddOptions{}),
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "list",
+				Description: "List feed rules",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "remove",
+				Description: "Remove a feed rule",
+				Options:     discord.CommandOptions(rssRemoveOptions{}),
+			},
+		},
+	}
+}
+
+type rssAddOptions struct {
+	URL            string `option:"url" description:"URL of the RSS or Atom feed" required:"true"`
+	Pattern        string `option:"pattern" description:"Regular expression matched against item titles" required:"true"`
+	Category       string `option:"category" description:"Category of added torrents"`
+	MinSize        uint64 `option:"min-size" description:"Minimum size in MiB" min:"0"`
+	MaxSize        uint64 `option:"max-size" description:"Maximum size in MiB" min:"0"`
+	DedupeEpisodes bool   `option:"dedupe-episodes" description:"Only add each episode once, defaults to true" default:"true"`
+}
+
+type rssRemoveOptions struct {
+	ID string `option:"id" description:"ID of the rule" required:"true"`
+}
+
+func (p *RSSCommand) Handle(ctx discord.Context) error {
+	options := ctx.Options()
+	var content string
+	var err error
+	switch options.SubCommand() {
+	case "add":
+		content, err = p.add(ctx, options)
+	case "list":
+		content, err = p.list(ctx)
+	case "remove":
+		content, err = p.remove(ctx, options)
+	default:
+		return unexpectedSubCommandError{options.SubCommand()}
+	}
+	if err != nil {
+		return err
+	}
+	if err := ctx.Respond(&discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags:   discordgo.MessageFlagsEphemeral,
+			Content: content,
+		},
+	}); err != nil {
+		return failedResponseInteractionError{err}
+	}
+	return nil
+}
+
+func (p *RSSCommand) add(ctx discord.Context, rawOptions discord.Options) (string, error) {
+	// Polly fetches whatever URL is added, so only admins can add feeds
+	if !isAdmin(ctx, p.sess, p.access) {
+		return "", errAdminRequired
+	}
+	var options rssAddOptions
+	if err := rawOptions.Decode(&options); err != nil {
+		return "", err
+	}
+	rule := &models.RSSRule{
+		ID:             uuid.NewString(),
+		Pattern:        options.Pattern,
+		MinSize:        options.MinSize * megabyte,
+		MaxSize:        options.MaxSize * megabyte,
+		DedupeEpisodes: options.DedupeEpisodes,
+		CreatedBy:      ctx.UserID(),
+		ChannelID:      ctx.ChannelID(),
+		GuildID:        ctx.Interaction.GuildID,
+	}
+	if _, err := rss.CompilePattern(rule.Pattern); err != nil {
+		return "", fmt.Errorf("failed to add rule: %w", err)
+	}
+	if options.Category != "" {
+		category, err := downloads.NormalizeCategory(options.Category)
+		if err != nil {
+			return "", fmt.Errorf("failed to add rule: %w", err)
+		}
+		rule.Category = category
+	}
+	feed := &models.RSSFeed{
+		URL:       strings.TrimSpace(options.URL),
+		CreatedBy: ctx.UserID(),
+	}
+	if err := models.GetOrCreateRSSFeed(ctx, p.sess, feed); err != nil {
+		return "", fmt.Errorf("failed to get feed: %w", err)
+	}
+	rule.FeedID = feed.ID
+	if err := rule.Create(ctx, p.sess); err != nil {
+		return "", fmt.Errorf("failed to create rule: %w", err)
+	}
+	return fmt.Sprintf("Added rule %s for %s", rule.ID, feed.URL), nil
+}
+
+// list is limited to admins, since feed URLs often carry a tracker passkey.
+func (p *RSSCommand) list(ctx discord.Context) (string, error) {
+	if !isAdmin(ctx, p.sess, p.access) {
+		return "", errAdminRequired
+	}
+	feeds, err := models.GetRSSFeeds(ctx, p.sess)
+	if err != nil {
+		return "", fmt.Errorf("failed to get feeds: %w", err)
+	}
+	content := make([]string, 0)
+	for _, feed := range feeds {
+		cond := models.InGuild(ctx.Interaction.GuildID)
+		cond["feed_id"] = feed.ID
+		rules, err := models.GetRSSRules(ctx, p.sess, cond)
+		if err != nil {
+			return "", fmt.Errorf("failed to get rules: %w", err)
+		}
+		// Feeds are shared, only show those with rules in this guild
+		if len(rules) == 0 {
+			continue
+		}
+		content = append(content, feed.URL)
+		for _, rule := range rules {
+			line := fmt.Sprintf("- %s: `%s`", rule.ID, rule.Pattern)
+			if rule.Category != "" {
+				line += fmt.Sprintf(" as %s", rule.Category)
+			}
+			if rule.MinSize != 0 || rule.MaxSize != 0 {
+				line += fmt.Sprintf(" sized %s to %s", formatBytes(rule.MinSize), formatBytes(rule.MaxSize))
+			}
+			content = append(content, line)
+		}
+	}
+	if len(content) == 0 {
+		return "No feeds found", nil
+	}
+	return strings.Join(content, "\n"), nil
+}
+
+func (p *RSSCommand) remove(ctx discord.Context, rawOptions discord.Options) (string, error) {
+	var options rssRemoveOptions
+	if err := rawOptions.Decode(&options); err != nil {
+		return "", err
+	}
+	rule := &models.RSSRule{
+		ID: strings.TrimSpace(options.ID),
+	}
+	rules, err := models.GetRSSRules(ctx, p.sess, "id", rule.ID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get rule: %w", err)
+	}
+	if len(rules) == 0 {
+		return "", errUnknownRule
+	}
+	if !getAdminScope(ctx, p.sess, p.access).administers(ctx, rules[0].GuildID) {
+		return "", errAdminRequired
+	}
+	if err := rule.Delete(ctx, p.sess); err != nil {
+		return "", fmt.Errorf("failed to remove rule: %w", err)
+	}
+	return fmt.Sprintf("Removed rule %s", rule.ID), nil
+}
@@ -0,0 +1,189 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bobcob7/polly-bot/internal/config"
+	"github.com/bobcob7/polly-bot/internal/downloads"
+	"github.com/bobcob7/polly-bot/internal/models"
+	"github.com/bobcob7/polly-bot/pkg/discord"
+	"github.com/bwmarrin/discordgo"
+	"github.com/upper/db/v4"
+)
+
+var errGuildRequired = errors.New("settings can only be changed within a server")
+
+type SettingsCommand struct {
+	sess   db.Session
+	access config.Access
+}
+
+func NewSettingsCommand(sess db.Session, access config.Access) *SettingsCommand {
+	return &SettingsCommand{
+		sess:   sess,
+		access: access,
+	}
+}
+
+func (p *SettingsCommand) Name() string {
+	return "settings"
+}
+
+func (p *SettingsCommand) Command() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        p.Name(),
+		Description: "Shows and changes Polly's settings for this server",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "show",
+				Description: "Show this server's settings",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "notifications",
+				Description: "Announce completed downloads in a channel",
+				Options:     discord.CommandOptions(settingsNotificationsOptions{}),
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "admin-role",
+				Description: "Add or remove a role which administers Polly in this server",
+				Options:     discord.CommandOptions(settingsAdminRoleOptions{}),
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "categories",
+				Description: "Restrict the categories torrents can use",
+				Options:     discord.CommandOptions(settingsCategoriesOptions{}),
+			},
+		},
+	}
+}
+
+type settingsNotificationsOptions struct {
+	Channel discord.ChannelID `option:"channel" description:"Channel to announce in, leave empty to stop announcing"`
+}
+
+type settingsAdminRoleOptions struct {
+	Role discord.RoleID `option:"role" description:"Role to toggle" required:"true"`
+}
+
+type settingsCategoriesOptions struct {
+	Categories string `option:"categories" description:"Comma separated categories, leave empty to allow all"`
+}
+
+func (p *SettingsCommand) Handle(ctx discord.Context) error {
+	guildID := ctx.Interaction.GuildID
+	if guildID == "" {
+		return errGuildRequired
+	}
+	options := ctx.Options()
+	settings, err := models.GetGuildSettings(ctx, p.sess, guildID)
+	if err != nil {
+		return fmt.Errorf("failed to get settings: %w", err)
+	}
+	if options.SubCommand() != "show" {
+		if !isAdmin(ctx, p.sess, p.access) {
+			return errAdminRequired
+		}
+		if err := p.change(settings, options); err != nil {
+			return err
+		}
+		settings.UpdatedBy = ctx.UserID()
+		if err := settings.Set(ctx, p.sess); err != nil {
+			return fmt.Errorf("failed to save settings: %w", err)
+		}
+	}
+	if err := ctx.Respond(&discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags:   discordgo.MessageFlagsEphemeral,
+			Content: settingsMessage(settings),
+		},
+	}); err != nil {
+		return failedResponseInteractionError{err}
+	}
+	return nil
+}
+
+// change applies the sub command to the settings.
+func (p *SettingsCommand) change(settings *models.GuildSettings, rawOptions discord.Options) error {
+	switch rawOptions.SubCommand() {
+	case "notifications":
+		var options settingsNotificationsOptions
+		if err := rawOptions.Decode(&options); err != nil {
+			return err
+		}
+		settings.NotificationChannelID = string(options.Channel)
+	case "admin-role":
+		var options settingsAdminRoleOptions
+		if err := rawOptions.Decode(&options); err != nil {
+			return err
+		}
+		settings.AdminRoles = strings.Join(toggle(settings.AdminRoleIDs(), string(options.Role)), ",")
+	case "categories":
+		var options settingsCategoriesOptions
+		if err := rawOptions.Decode(&options); err != nil {
+			return err
+		}
+		categories, err := normalizeCategories(options.Categories)
+		if err != nil {
+			return err
+		}
+		settings.Categories = strings.Join(categories, ",")
+	default:
+		return unexpectedSubCommandError{rawOptions.SubCommand()}
+	}
+	return nil
+}
+
+func settingsMessage(settings *models.GuildSettings) string {
+	notifications := "none"
+	if settings.NotificationChannelID != "" {
+		notifications = fmt.Sprintf("<#%s>", settings.NotificationChannelID)
+	}
+	roles := make([]string, 0, len(settings.AdminRoleIDs()))
+	for _, role := range settings.AdminRoleIDs() {
+		roles = append(roles, fmt.Sprintf("<@&%s>", role))
+	}
+	if len(roles) == 0 {
+		roles = append(roles, "none")
+	}
+	categories := "all"
+	if settings.Categories != "" {
+		categories = strings.Join(settings.AllowedCategories(), ", ")
+	}
+	return fmt.Sprintf("Notification channel: %s\nAdmin roles: %s\nCategories: %s", notifications, strings.Join(roles, " "), categories)
+}
+
+// toggle removes the value from the list if it is present, otherwise adds it.
+func toggle(list []string, value string) []string {
+	output := make([]string, 0, len(list)+1)
+	for _, item := range list {
+		if item != value {
+			output = append(output, item)
+		}
+	}
+	if len(output) == len(list) {
+		output = append(output, value)
+	}
+	return output
+}
+
+func normalizeCategories(rawCategories string) ([]string, error) {
+	output := make([]string, 0)
+	for _, rawCategory := range strings.Split(rawCategories, ",") {
+		if strings.TrimSpace(rawCategory) == "" {
+			continue
+		}
+		category, err := downloads.NormalizeCategory(rawCategory)
+		if err != nil {
+			return nil, err
+		}
+		output = append(output, category)
+	}
+	return output, nil
+}
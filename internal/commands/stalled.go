@@ -0,0 +1,186 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bobcob7/polly-bot/internal/config"
+	"github.com/bobcob7/polly-bot/internal/models"
+	"github.com/bobcob7/polly-bot/internal/tracing"
+	"github.com/bobcob7/polly-bot/pkg/discord"
+	"github.com/bwmarrin/discordgo"
+	"github.com/upper/db/v4"
+	"go.uber.org/zap"
+)
+
+const (
+	stalledComponentPrefix = "stalled:"
+	stalledKeepAction      = "keep"
+	stalledRemoveAction    = "remove"
+)
+
+var errRemoveNotAllowed = errors.New("only admins or whoever added the torrent can remove it")
+
+type StalledCommand struct {
+	StalledTorrents chan *models.Torrent
+	sess            db.Session
+	tx              *tracing.Transmission
+	keepWindow      time.Duration
+	access          config.Access
+}
+
+func NewStalledCommand(sess db.Session, tx *tracing.Transmission, keepWindow time.Duration, access config.Access) *StalledCommand {
+	return &StalledCommand{
+		StalledTorrents: make(chan *models.Torrent),
+		sess:            sess,
+		tx:              tx,
+		keepWindow:      keepWindow,
+		access:          access,
+	}
+}
+
+func (p *StalledCommand) Name() string {
+	return "stalled"
+}
+
+func (p *StalledCommand) Command() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        p.Name(),
+		Description: "Lists torrents which stopped making progress",
+	}
+}
+
+func (p *StalledCommand) Handle(ctx discord.Context) error {
+	torrents, err := models.GetTorrentsWithLabel(ctx, p.sess, models.StalledLabel)
+	if err != nil {
+		return fmt.Errorf("failed to get stalled torrents from db: %w", err)
+	}
+	content := make([]string, 0, len(torrents))
+	for _, torrent := range torrents {
+		if !torrent.VisibleIn(ctx.Interaction.GuildID) {
+			continue
+		}
+		since, _ := torrent.Label(models.StalledLabel)
+		content = append(content, fmt.Sprintf("%s, stalled since %s", torrent.String(), since))
+	}
+	found := len(content)
+	if found == 0 {
+		content = append(content, "No stalled torrents")
+	}
+	if err := ctx.Respond(&discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Title:   fmt.Sprintf("Found %d stalled torrents", found),
+			Flags:   discordgo.MessageFlagsEphemeral,
+			Content: strings.Join(content, "\n"),
+		},
+	}); err != nil {
+		return failedResponseInteractionError{err}
+	}
+	return nil
+}
+
+func (p *StalledCommand) HasComponentID(id string) bool {
+	return strings.HasPrefix(id, stalledComponentPrefix)
+}
+
+func (p *StalledCommand) HandleComponent(ctx discord.Context, id string) error {
+	action, torrentID, ok := strings.Cut(strings.TrimPrefix(id, stalledComponentPrefix), ":")
+	if !ok {
+		return discord.ErrNotFound
+	}
+	torrent := &models.Torrent{ID: torrentID}
+	if err := torrent.Get(ctx, p.sess); err != nil {
+		return fmt.Errorf("failed to get torrent from db: %w", err)
+	}
+	var content string
+	switch action {
+	case stalledKeepAction:
+		keepUntil := time.Now().Add(p.keepWindow).UTC()
+		if err := torrent.DeleteLabel(ctx, p.sess, models.StalledLabel); err != nil {
+			return fmt.Errorf("failed to clear stalled label: %w", err)
+		}
+		if err := torrent.SetLabel(ctx, p.sess, models.StalledKeepUntilLabel, keepUntil.Format(time.RFC3339)); err != nil {
+			return fmt.Errorf("failed to set keep label: %w", err)
+		}
+		content = fmt.Sprintf("Still waiting for %s", torrent.NameString())
+	case stalledRemoveAction:
+		// Removing deletes the data, so anyone who can see the message mustn't be able to
+		requester := torrent.TorrentMetadata != nil && torrent.RequestedBy != "" && torrent.RequestedBy == ctx.UserID()
+		var guildID string
+		if torrent.TorrentMetadata != nil {
+			guildID = torrent.GuildID
+		}
+		if !requester && !getAdminScope(ctx, p.sess, p.access).administers(ctx, guildID) {
+			return errRemoveNotAllowed
+		}
+		if torrent.DeletedAt == nil {
+			id, err := strconv.Atoi(torrent.ID)
+			if err != nil {
+				return fmt.Errorf("invalid torrent id %q: %w", torrent.ID, err)
+			}
+			if err := p.tx.RemoveTorrents(ctx, true, id); err != nil {
+				return fmt.Errorf("failed to remove torrent: %w", err)
+			}
+			if err := torrent.MarkDeleted(ctx, p.sess); err != nil {
+				return fmt.Errorf("failed to mark torrent deleted: %w", err)
+			}
+		}
+		content = fmt.Sprintf("Removed %s", torrent.NameString())
+	default:
+		return discord.ErrNotFound
+	}
+	ctx.Logger().Info("handled stalled torrent", zap.String("action", action), zap.String("torrentID", torrent.ID))
+	if err := ctx.Respond(&discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    content,
+			Components: []discordgo.MessageComponent{},
+		},
+	}); err != nil {
+		return failedResponseInteractionError{err}
+	}
+	return nil
+}
+
+// OnStart sends stalled notifications until the bot stops.
+func (p *StalledCommand) OnStart(ctx discord.Context, s *discordgo.Session) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case torrent := <-p.StalledTorrents:
+			notifyTorrentSubscribers(ctx, p.sess, torrent, stalledMessage(torrent))
+		}
+	}
+}
+
+func stalledMessage(torrent *models.Torrent) *discordgo.MessageSend {
+	if torrent.DeletedAt != nil {
+		return &discordgo.MessageSend{
+			Content: fmt.Sprintf("Removed stalled download: %s", torrent.NameString()),
+		}
+	}
+	return &discordgo.MessageSend{
+		Content: fmt.Sprintf("Download stopped making progress: %s", torrent.String()),
+		Components: []discordgo.MessageComponent{
+			discordgo.ActionsRow{
+				Components: []discordgo.MessageComponent{
+					discordgo.Button{
+						Label:    "Keep waiting",
+						Style:    discordgo.SecondaryButton,
+						CustomID: stalledComponentPrefix + stalledKeepAction + ":" + torrent.ID,
+					},
+					discordgo.Button{
+						Label:    "Remove",
+						Style:    discordgo.DangerButton,
+						CustomID: stalledComponentPrefix + stalledRemoveAction + ":" + torrent.ID,
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,102 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bobcob7/polly-bot/internal/models"
+	"github.com/bobcob7/polly-bot/pkg/discord"
+	"github.com/bwmarrin/discordgo"
+	"github.com/upper/db/v4"
+)
+
+// statusLimit caps how many torrents are listed so the reply fits in a message.
+const statusLimit = 10
+
+type StatusCommand struct {
+	sess       db.Session
+	rateWindow time.Duration
+}
+
+func NewStatusCommand(sess db.Session, rateWindow time.Duration) *StatusCommand {
+	return &StatusCommand{
+		sess:       sess,
+		rateWindow: rateWindow,
+	}
+}
+
+func (p *StatusCommand) Name() string {
+	return "status"
+}
+
+func (p *StatusCommand) Command() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        p.Name(),
+		Description: "Shows speeds and ETAs of torrents",
+		Options:     discord.CommandOptions(statusOptions{}),
+	}
+}
+
+type statusOptions struct {
+	All bool `option:"all" description:"Include finished torrents"`
+}
+
+func (p *StatusCommand) Handle(ctx discord.Context) error {
+	var options statusOptions
+	if err := ctx.Options().Decode(&options); err != nil {
+		return err
+	}
+	cond := models.InGuild(ctx.Interaction.GuildID)
+	cond["deleted_at"] = db.IsNull()
+	if !options.All {
+		cond["completed_at"] = db.IsNull()
+	}
+	torrents, err := models.GetTorrents(ctx, p.sess, statusLimit, cond)
+	if err != nil {
+		return fmt.Errorf("failed to get torrents from db: %w", err)
+	}
+	content := make([]string, 0, len(torrents))
+	for _, torrent := range torrents {
+		stats, err := torrent.GetStats(ctx, p.sess, p.rateWindow)
+		if err != nil {
+			return fmt.Errorf("failed to get torrent stats: %w", err)
+		}
+		content = append(content, statusLine(torrent, stats))
+	}
+	if len(content) == 0 {
+		content = append(content, "No torrents found")
+	}
+	if err := ctx.Respond(&discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Title:   fmt.Sprintf("Found %d torrents", len(torrents)),
+			Flags:   discordgo.MessageFlagsEphemeral,
+			Content: strings.Join(content, "\n"),
+		},
+	}); err != nil {
+		return failedResponseInteractionError{err}
+	}
+	return nil
+}
+
+func statusLine(torrent *models.Torrent, stats models.TorrentStats) string {
+	line := fmt.Sprintf("%s, ↓ %s/s ↑ %s/s", torrent.String(), formatBytes(uint64(stats.DownloadRate)), formatBytes(uint64(stats.UploadRate)))
+	if stats.ETA != 0 {
+		line += fmt.Sprintf(", ETA %s", stats.ETA)
+	}
+	return line + fmt.Sprintf(", %s uploaded in total", formatBytes(torrent.Uploaded))
+}
+
+func formatBytes(b uint64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+	div, exp := uint64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(b)/float64(div), "KMGTPE"[exp])
+}
@@ -3,7 +3,9 @@ package commands
 import (
 	"fmt"
 
+	"github.com/bobcob7/polly-bot/internal/metrics"
 	"github.com/bobcob7/polly-bot/internal/models"
+	"github.com/bobcob7/polly-bot/internal/organizer"
 	"github.com/bobcob7/polly-bot/pkg/discord"
 	"github.com/bwmarrin/discordgo"
 	"github.com/upper/db/v4"
@@ -12,44 +14,97 @@ import (
 
 type TorrentNotifier struct {
 	CompletedTorrents chan *models.Torrent
+	Extractions       chan *organizer.Extraction
 	dbSession         db.Session
 }
 
 func NewTorrentNotifier(dbSession db.Session) *TorrentNotifier {
 	return &TorrentNotifier{
 		CompletedTorrents: make(chan *models.Torrent),
+		Extractions:       make(chan *organizer.Extraction),
 		dbSession:         dbSession,
 	}
 }
 
+// OnStart sends notifications until the bot stops.
 func (t *TorrentNotifier) OnStart(ctx discord.Context, s *discordgo.Session) error {
-	go func() {
-		logger := ctx.Logger()
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case torrent := <-t.CompletedTorrents:
-				logger.Info("completed torrent", zap.String("name", torrent.NameString()))
-				notifications, err := models.GetTorrentNotifications(ctx, t.dbSession, torrent.ID)
-				if err != nil {
-					logger.Error("failed to get notifications", zap.Error(err))
-				}
-				for _, notification := range notifications {
-					content := fmt.Sprintf("Completed download: %s", torrent.NameString())
-					if notification.RecipientID != "" {
-						if err := ctx.PrivateMessenger.SendMessage(ctx, notification.RecipientID, content); err != nil {
-							logger.Error("failed to send notification", zap.Error(err), zap.String("recipientID", notification.RecipientID))
-						}
-					}
-					if notification.ChannelID != "" {
-						if _, err := ctx.Session.ChannelMessageSend(notification.ChannelID, content); err != nil {
-							logger.Error("failed to send notification", zap.Error(err), zap.String("channelID", notification.ChannelID))
-						}
-					}
-				}
+	logger := ctx.Logger()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case torrent := <-t.CompletedTorrents:
+			logger.Info("completed torrent", zap.String("name", torrent.NameString()))
+			msg := &discordgo.MessageSend{
+				Content: fmt.Sprintf("Completed download: %s", torrent.NameString()),
 			}
+			notifyTorrentSubscribers(ctx, t.dbSession, torrent, msg)
+			notifyGuild(ctx, t.dbSession, torrent, msg)
+		case extraction := <-t.Extractions:
+			notifyTorrentSubscribers(ctx, t.dbSession, extraction.Torrent, extractionMessage(extraction))
 		}
-	}()
-	return nil
+	}
+}
+
+// Notification targets, as labelled in metrics.
+const (
+	notificationTargetDirect  = "direct"
+	notificationTargetChannel = "channel"
+	notificationTargetGuild   = "guild"
+)
+
+func extractionMessage(extraction *organizer.Extraction) *discordgo.MessageSend {
+	if extraction.Err != nil {
+		return &discordgo.MessageSend{
+			Content: fmt.Sprintf("Failed to extract %s from %s: %v", extraction.Archive, extraction.Torrent.NameString(), extraction.Err),
+		}
+	}
+	return &discordgo.MessageSend{
+		Content: fmt.Sprintf("Extracted %s from %s", extraction.Archive, extraction.Torrent.NameString()),
+	}
+}
+
+// notifyGuild sends the message to the notification channel of the guild the torrent was added in.
+func notifyGuild(ctx discord.Context, sess db.Session, torrent *models.Torrent, msg *discordgo.MessageSend) {
+	if torrent.TorrentMetadata == nil || torrent.GuildID == "" {
+		return
+	}
+	settings, err := models.GetGuildSettings(ctx, sess, torrent.GuildID)
+	if err != nil {
+		ctx.Logger().Error("failed to get guild settings", zap.Error(err), zap.String("guildID", torrent.GuildID))
+		return
+	}
+	if settings.NotificationChannelID == "" {
+		return
+	}
+	_, err = ctx.Session.ChannelMessageSendComplex(settings.NotificationChannelID, msg)
+	metrics.Notification(notificationTargetGuild, err)
+	if err != nil {
+		ctx.Logger().Error("failed to send guild notification", zap.Error(err), zap.String("guildID", torrent.GuildID))
+	}
+}
+
+// notifyTorrentSubscribers sends the message to every recipient and channel subscribed to the torrent.
+func notifyTorrentSubscribers(ctx discord.Context, sess db.Session, torrent *models.Torrent, msg *discordgo.MessageSend) {
+	logger := ctx.Logger()
+	notifications, err := models.GetTorrentNotifications(ctx, sess, torrent.ID)
+	if err != nil {
+		logger.Error("failed to get notifications", zap.Error(err))
+	}
+	for _, notification := range notifications {
+		if notification.RecipientID != "" {
+			err := ctx.PrivateMessenger.SendComplexMessage(ctx, notification.RecipientID, msg)
+			metrics.Notification(notificationTargetDirect, err)
+			if err != nil {
+				logger.Error("failed to send notification", zap.Error(err), zap.String("recipientID", notification.RecipientID))
+			}
+		}
+		if notification.ChannelID != "" {
+			_, err := ctx.Session.ChannelMessageSendComplex(notification.ChannelID, msg)
+			metrics.Notification(notificationTargetChannel, err)
+			if err != nil {
+				logger.Error("failed to send notification", zap.Error(err), zap.String("channelID", notification.ChannelID))
+			}
+		}
+	}
 }
@@ -13,6 +13,10 @@ func (p *WhoAmI) Name() string {
 	return "whoami"
 }
 
+func (p *WhoAmI) Ephemeral() bool {
+	return false
+}
+
 func (p *WhoAmI) Command() *discordgo.ApplicationCommand {
 	return &discordgo.ApplicationCommand{
 		Name:        p.Name(),
@@ -41,7 +45,7 @@ Roles:	%v`,
 	if err := ctx.PrivateMessenger.SendMessage(ctx, ctx.Interaction.Member.User.ID, content); err != nil {
 		return failedResponseInteractionError{err}
 	}
-	if err := ctx.Session.InteractionRespond(ctx.Interaction, &discordgo.InteractionResponse{
+	if err := ctx.Respond(&discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
 			Title:   "Who Am I?",
@@ -3,8 +3,11 @@ package config
 import (
 	"fmt"
 	"net/url"
+	"text/template"
 	"time"
 
+	"github.com/bobcob7/polly-bot/internal/models"
+	"github.com/bobcob7/polly-bot/internal/tracing"
 	"github.com/bobcob7/polly-bot/pkg/discord"
 	"github.com/upper/db/v4"
 	"github.com/upper/db/v4/adapter/cockroachdb"
@@ -13,9 +16,62 @@ import (
 
 func New() *Config {
 	return &Config{
+		ShutdownTimeout: 30 * time.Second,
+		Discord: discord.Config{
+			DeferAfter:     2 * time.Second,
+			HandlerTimeout: time.Minute,
+		},
 		Transmission: Transmission{
 			Endpoint:          "https://transmission.bobcob7.com",
 			DownloadDirectory: "/downloads/complete",
+			Scraper: TransmissionScraper{
+				MinPeriod:            2 * time.Second,
+				MaxPeriod:            5 * time.Minute,
+				SampleResolution:     time.Minute,
+				SampleRetention:      30 * 24 * time.Hour,
+				DownsampleAfter:      24 * time.Hour,
+				DownsampleResolution: time.Hour,
+				RateWindow:           15 * time.Minute,
+			},
+			Stalled: TransmissionStalled{
+				Window: time.Hour,
+			},
+			DiskGuard: TransmissionDiskGuard{
+				Reserve: 1 << 30,
+				Action:  DiskGuardActionReject,
+			},
+		},
+		RSS: RSS{
+			Period:        15 * time.Minute,
+			HistoryLength: 1000,
+			MaxAttempts:   5,
+		},
+		Watch: Watch{
+			Period: 10 * time.Second,
+		},
+		GRPC: GRPC{
+			H2C:          true,
+			ReloadPeriod: time.Minute,
+			Auth: GRPCAuth{
+				RateLimit:        120,
+				ClientCertScopes: []string{models.ScopeRead},
+			},
+		},
+		Tracing: tracing.Config{
+			SampleRatio: 1,
+		},
+		Health: Health{
+			ScrapeMaxAge: 15 * time.Minute,
+			Timeout:      5 * time.Second,
+		},
+		Ops: Ops{
+			Address: ":9090",
+		},
+		Organizer: Organizer{
+			Mode: OrganizeModeHardlink,
+			Extract: OrganizerExtract{
+				RarCommand: "unrar x -o- {archive} {destination}/",
+			},
 		},
 	}
 }
@@ -25,10 +81,254 @@ type Config struct {
 	Discord      discord.Config
 	Transmission Transmission
 	GRPC         GRPC `map:"GRPC"`
+	RSS          RSS  `map:"RSS"`
+	Watch        Watch
+	Organizer    Organizer
+	Access       Access
+	Quotas       Quotas
+	Health       Health
+	Ops          Ops
+	Tracing      tracing.Config
+	// ShutdownTimeout is how long in-flight work gets to finish after a shutdown signal.
+	ShutdownTimeout time.Duration `map:"SHUTDOWN_TIMEOUT"`
+}
+
+type RSS struct {
+	// Period is the time between polling all feeds.
+	Period time.Duration
+	// HistoryLength is how many seen items are remembered per feed.
+	HistoryLength int `map:"HISTORY_LENGTH"`
+	// MaxAttempts is how many polls try to add an item before it's given up on and marked seen.
+	MaxAttempts int `map:"MAX_ATTEMPTS"`
+}
+
+func (c RSS) Valid() (errs MultiError) {
+	if c.Period <= 0 {
+		errs.Add("RSS Period must be positive")
+	}
+	if c.HistoryLength <= 0 {
+		errs.Add("RSS HistoryLength must be positive")
+	}
+	if c.MaxAttempts <= 0 {
+		errs.Add("RSS MaxAttempts must be positive")
+	}
+	return
+}
+
+type Watch struct {
+	// Directory is polled for .torrent and .magnet files, watching is disabled when empty.
+	Directory string
+	// Period is the time between scans of the directory.
+	Period time.Duration
+}
+
+func (c Watch) Valid() (errs MultiError) {
+	if c.Directory != "" && c.Period <= 0 {
+		errs.Add("Watch Period must be positive")
+	}
+	return
+}
+
+const (
+	OrganizeModeMove     = "move"
+	OrganizeModeCopy     = "copy"
+	OrganizeModeHardlink = "hardlink"
+)
+
+// Organizer puts the files of completed torrents into media libraries.
+type Organizer struct {
+	// Mode is one of "move", "copy" or "hardlink", only copy and hardlink keep the torrent seeding.
+	Mode string
+	// DryRun only logs what would be done.
+	DryRun    bool `map:"DRY_RUN"`
+	Libraries []Library
+	Extract   OrganizerExtract
+}
+
+// OrganizerExtract extracts archives in completed torrents into their library, or next to them without one.
+type OrganizerExtract struct {
+	Enabled bool
+	// RarCommand extracts rar archives, "{archive}" and "{destination}" in its arguments are replaced.
+	// Rar archives fail to extract when it is empty.
+	RarCommand string `map:"RAR_COMMAND"`
+}
+
+func (c Organizer) Valid() (errs MultiError) {
+	switch c.Mode {
+	case OrganizeModeMove:
+	case OrganizeModeCopy:
+	case OrganizeModeHardlink:
+	default:
+		errs.Add(fmt.Sprintf("Organizer Mode is unsupported: %q", c.Mode))
+	}
+	for i, library := range c.Libraries {
+		for _, err := range library.Valid().e {
+			errs.Add(fmt.Sprintf("Organizer Libraries[%d] %s", i, err))
+		}
+	}
+	return
+}
+
+// Library is where completed torrents of a category are organized into.
+// A library with an empty category applies to torrents no other library matches.
+type Library struct {
+	Category  string
+	Directory string
+	// Layout is a text/template of the path within Directory, e.g. "{{.Name}}".
+	Layout string
+}
+
+func (c Library) Valid() (errs MultiError) {
+	if c.Directory == "" {
+		errs.Add("Directory is required")
+	}
+	if c.Layout == "" {
+		errs.Add("Layout is required")
+	} else if _, err := template.New("layout").Parse(c.Layout); err != nil {
+		errs.Add(fmt.Sprintf("Layout is invalid: %v", err))
+	}
+	return
+}
+
+// Access decides who can administer Polly, by Discord user and role IDs.
+type Access struct {
+	AdminUsers []string `map:"ADMIN_USERS"`
+	AdminRoles []string `map:"ADMIN_ROLES"`
+	// ModerationChannelID is where torrents added by non admins wait for approval,
+	// everyone can add torrents directly when it is empty.
+	ModerationChannelID string `map:"MODERATION_CHANNEL_ID"`
+	// AuditChannelID is where every command and RPC is mirrored, nothing is mirrored when it is empty.
+	AuditChannelID string `map:"AUDIT_CHANNEL_ID"`
+}
+
+func (c Access) IsAdmin(userID string, roles []string) bool {
+	for _, adminID := range c.AdminUsers {
+		if adminID == userID {
+			return true
+		}
+	}
+	for _, adminRole := range c.AdminRoles {
+		for _, role := range roles {
+			if adminRole == role {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Quotas limit how much each user can download, admins are never limited.
+type Quotas struct {
+	// Default applies to users without a role quota.
+	Default Quota
+	// Roles give members of a role a different quota, the most generous one applies.
+	Roles []RoleQuota
+}
+
+func (c Quotas) Valid() (errs MultiError) {
+	for i, role := range c.Roles {
+		if role.RoleID == "" {
+			errs.Add(fmt.Sprintf("Quotas Roles[%d] RoleID is required", i))
+		}
+	}
+	return
+}
+
+// Quota limits a user's downloads, zero values are unlimited.
+type Quota struct {
+	// MaxActive is how many unfinished torrents a user can have.
+	MaxActive int `map:"MAX_ACTIVE"`
+	// DailyBytes and WeeklyBytes limit the size of torrents a user adds within a day or week.
+	DailyBytes  uint64 `map:"DAILY_BYTES"`
+	WeeklyBytes uint64 `map:"WEEKLY_BYTES"`
+	// Blocked users can't add anything, unlike zero limits which are unlimited.
+	Blocked bool
+}
+
+type RoleQuota struct {
+	RoleID string `map:"ROLE_ID"`
+	Quota  Quota
 }
 
 type GRPC struct {
 	Address string
+	// H2C serves cleartext HTTP/2 which gRPC clients require, alongside HTTP/1.1 for Connect and REST clients.
+	H2C bool
+	// CertFile and KeyFile serve RPCs over TLS, they are served in cleartext when both are empty.
+	CertFile string `map:"CERT_FILE"`
+	KeyFile  string `map:"KEY_FILE"`
+	// ReloadPeriod is how often CertFile and KeyFile are checked for changes, so renewed certificates are used.
+	ReloadPeriod time.Duration `map:"RELOAD_PERIOD"`
+	// ClientCAFile verifies client certificates, callers presenting one are authenticated without an API key.
+	ClientCAFile string `map:"CLIENT_CA_FILE"`
+	Auth         GRPCAuth
+}
+
+func (c GRPC) Valid() (errs MultiError) {
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		errs.Add("GRPC CertFile and KeyFile must be set together")
+	}
+	if c.ClientCAFile != "" && c.CertFile == "" {
+		errs.Add("GRPC ClientCAFile requires TLS")
+	}
+	if c.CertFile != "" && c.ReloadPeriod <= 0 {
+		errs.Add("GRPC ReloadPeriod must be positive")
+	}
+	errs.Append(c.Auth.Valid())
+	return
+}
+
+// GRPCAuth configures how RPC callers are authenticated, with API keys or client certificates.
+type GRPCAuth struct {
+	// Disabled lets anyone call every RPC, only use it behind a proxy which authenticates callers.
+	Disabled bool
+	// RateLimit is how many RPCs per minute a caller can make unless its key sets a limit, zero is unlimited.
+	RateLimit int `map:"RATE_LIMIT"`
+	// ClientCertScopes are granted to callers authenticated by a client certificate.
+	ClientCertScopes []string `map:"CLIENT_CERT_SCOPES"`
+}
+
+func (c GRPCAuth) Valid() (errs MultiError) {
+	if c.RateLimit < 0 {
+		errs.Add("GRPC Auth RateLimit must not be negative")
+	}
+	for _, scope := range c.ClientCertScopes {
+		if !models.ValidScope(scope) {
+			errs.Add(fmt.Sprintf("GRPC Auth ClientCertScopes has an unsupported scope: %q", scope))
+		}
+	}
+	return
+}
+
+// Health configures the checks served on /healthz and /readyz.
+type Health struct {
+	// ScrapeMaxAge is how old the last successful scrape can be before Polly isn't ready.
+	ScrapeMaxAge time.Duration `map:"SCRAPE_MAX_AGE"`
+	// Timeout limits each check.
+	Timeout time.Duration
+}
+
+func (c Health) Valid() (errs MultiError) {
+	if c.ScrapeMaxAge <= 0 {
+		errs.Add("Health Scrape Max Age must be positive")
+	}
+	if c.Timeout <= 0 {
+		errs.Add("Health Timeout must be positive")
+	}
+	return
+}
+
+// Ops configures the listener for health checks and metrics, which is separate from the RPC server
+// so they don't need RPC credentials and can be kept off the public network.
+type Ops struct {
+	Address string
+}
+
+func (c Ops) Valid() (errs MultiError) {
+	if c.Address == "" {
+		errs.Add("Ops Address is required")
+	}
+	return
 }
 
 type Database struct {
@@ -125,6 +425,9 @@ type Transmission struct {
 	Endpoint          string
 	DownloadDirectory string
 	Scraper           TransmissionScraper
+	Stalled           TransmissionStalled
+	SeedingPolicies   []SeedingPolicy       `map:"SEEDING_POLICIES"`
+	DiskGuard         TransmissionDiskGuard `map:"DISK_GUARD"`
 }
 
 func (c Transmission) Valid() (errs MultiError) {
@@ -135,15 +438,131 @@ func (c Transmission) Valid() (errs MultiError) {
 	if c.DownloadDirectory == "" {
 		errs.Add("Transmission DownloadDirectory is required")
 	}
+	errs.Append(c.Scraper.Valid())
+	errs.Append(c.Stalled.Valid())
+	errs.Append(c.DiskGuard.Valid())
+	for i, policy := range c.SeedingPolicies {
+		for _, err := range policy.Valid().e {
+			errs.Add(fmt.Sprintf("Transmission SeedingPolicies[%d] %s", i, err))
+		}
+	}
 	return
 }
 
 type TransmissionScraper struct {
 	MinPeriod time.Duration
 	MaxPeriod time.Duration
+	// SampleResolution is the minimum time between two progress samples of the same torrent.
+	SampleResolution time.Duration `map:"SAMPLE_RESOLUTION"`
+	// SampleRetention is how long progress samples are kept before being deleted.
+	SampleRetention time.Duration `map:"SAMPLE_RETENTION"`
+	// Samples older than DownsampleAfter are thinned out to one per DownsampleResolution.
+	DownsampleAfter      time.Duration `map:"DOWNSAMPLE_AFTER"`
+	DownsampleResolution time.Duration `map:"DOWNSAMPLE_RESOLUTION"`
+	// RateWindow is how far back samples are used when calculating speeds and ETAs.
+	RateWindow time.Duration `map:"RATE_WINDOW"`
 }
 
 func (c TransmissionScraper) Valid() (errs MultiError) {
+	if c.MinPeriod <= 0 {
+		errs.Add("Transmission Scraper MinPeriod must be positive")
+	}
+	if c.MaxPeriod < c.MinPeriod {
+		errs.Add("Transmission Scraper MaxPeriod must not be less than MinPeriod")
+	}
+	if c.SampleResolution <= 0 {
+		errs.Add("Transmission Scraper SampleResolution must be positive")
+	}
+	if c.SampleRetention < c.DownsampleAfter {
+		errs.Add("Transmission Scraper SampleRetention must not be less than DownsampleAfter")
+	}
+	if c.DownsampleResolution < c.SampleResolution {
+		errs.Add("Transmission Scraper DownsampleResolution must not be less than SampleResolution")
+	}
+	if c.RateWindow < c.SampleResolution {
+		errs.Add("Transmission Scraper RateWindow must not be less than SampleResolution")
+	}
+	return
+}
+
+type TransmissionStalled struct {
+	// Window is how long a torrent must make no progress before it is flagged as stalled.
+	Window time.Duration
+	// RemoveAfter is how long a stalled torrent is kept before being removed, zero disables removal.
+	RemoveAfter time.Duration `map:"REMOVE_AFTER"`
+}
+
+func (c TransmissionStalled) Valid() (errs MultiError) {
+	if c.Window <= 0 {
+		errs.Add("Transmission Stalled Window must be positive")
+	}
+	if c.RemoveAfter < 0 {
+		errs.Add("Transmission Stalled RemoveAfter must not be negative")
+	}
+	return
+}
+
+const (
+	DiskGuardActionReject = "reject"
+	DiskGuardActionPause  = "pause"
+)
+
+// TransmissionDiskGuard checks the free space of the download directory before torrents are downloaded.
+type TransmissionDiskGuard struct {
+	Enabled bool
+	// Reserve is how many bytes must stay free after a torrent finishes downloading.
+	Reserve uint64
+	// Action is either "reject" to remove torrents which don't fit or "pause" to stop them.
+	Action string
+}
+
+func (c TransmissionDiskGuard) Valid() (errs MultiError) {
+	switch c.Action {
+	case DiskGuardActionReject:
+	case DiskGuardActionPause:
+	default:
+		errs.Add(fmt.Sprintf("Transmission DiskGuard Action is unsupported: %q", c.Action))
+	}
+	return
+}
+
+const (
+	SeedingActionStop   = "stop"
+	SeedingActionRemove = "remove"
+)
+
+// SeedingPolicy decides when a completed torrent in a category has seeded enough.
+// A policy with an empty category applies to torrents no other policy matches.
+type SeedingPolicy struct {
+	Category string
+	// Ratio is the upload ratio after which seeding stops, once MinSeedTime has passed.
+	Ratio       float64
+	MinSeedTime time.Duration `map:"MIN_SEED_TIME"`
+	// MaxSeedTime stops seeding regardless of ratio, zero means no limit.
+	MaxSeedTime time.Duration `map:"MAX_SEED_TIME"`
+	// Action is either "stop" or "remove", removing always keeps the downloaded data.
+	Action string
+}
+
+func (c SeedingPolicy) Valid() (errs MultiError) {
+	if c.Ratio < 0 {
+		errs.Add("Ratio must not be negative")
+	}
+	if c.MinSeedTime < 0 || c.MaxSeedTime < 0 {
+		errs.Add("seed times must not be negative")
+	}
+	if c.Ratio == 0 && c.MinSeedTime == 0 && c.MaxSeedTime == 0 {
+		errs.Add("one of Ratio, MinSeedTime or MaxSeedTime is required")
+	}
+	if c.MaxSeedTime != 0 && c.MaxSeedTime < c.MinSeedTime {
+		errs.Add("MaxSeedTime must not be less than MinSeedTime")
+	}
+	switch c.Action {
+	case SeedingActionStop:
+	case SeedingActionRemove:
+	default:
+		errs.Add(fmt.Sprintf("unsupported Action: %q", c.Action))
+	}
 	return
 }
 
@@ -178,5 +597,19 @@ func (c Config) Valid() (errs MultiError) {
 	errs.Append(c.Transmission.Valid())
 	errs.Add(c.Discord.Valid()...)
 	errs.Append(c.Database.Valid())
+	errs.Append(c.RSS.Valid())
+	errs.Append(c.Watch.Valid())
+	errs.Append(c.Organizer.Valid())
+	errs.Append(c.Quotas.Valid())
+	errs.Append(c.Health.Valid())
+	errs.Append(c.Ops.Valid())
+	errs.Append(c.GRPC.Valid())
+	errs.Add(c.Tracing.Valid()...)
+	if c.Health.ScrapeMaxAge <= c.Transmission.Scraper.MaxPeriod {
+		errs.Add("Health Scrape Max Age must be longer than the Transmission Scraper Max Period")
+	}
+	if c.ShutdownTimeout <= 0 {
+		errs.Add("Shutdown Timeout must be positive")
+	}
 	return
 }
@@ -0,0 +1,185 @@
+package downloads
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bobcob7/polly-bot/internal/config"
+	"github.com/bobcob7/polly-bot/internal/models"
+	"github.com/bobcob7/polly-bot/internal/torrent"
+	"github.com/bobcob7/polly-bot/internal/tracing"
+	"github.com/google/uuid"
+	"github.com/upper/db/v4"
+	"go.uber.org/zap"
+)
+
+var ValidCategories = []string{
+	"MOVIE",
+	"TV SHOW",
+	"MUSIC",
+	"AUDIOBOOK",
+	"BOOK",
+	"SOFTWARE",
+}
+
+// NormalizeCategory returns the known category matching the raw input, ignoring case and whitespace.
+func NormalizeCategory(rawCategory string) (string, error) {
+	category := strings.ToUpper(strings.TrimSpace(rawCategory))
+	for _, knownCategory := range ValidCategories {
+		if category == knownCategory {
+			return category, nil
+		}
+	}
+	return "", unexpectedCategoryError{rawCategory}
+}
+
+// Request describes a magnet link or .torrent file to add to transmission.
+type Request struct {
+	MagnetLink string
+	// Metainfo is the contents of a .torrent file, added instead of MagnetLink if set.
+	Metainfo     []byte
+	FriendlyName string
+	// Category is optional and must be one of ValidCategories.
+	Category string
+	// RecipientID and ChannelID are notified once the download completes, if set.
+	RecipientID string
+	ChannelID   string
+	// RequestedBy is the Discord user adding the torrent, whose quota it counts towards.
+	RequestedBy string
+	Roles       []string
+	// Unmetered skips the quota check for automatic adds, the torrent still counts towards the usage.
+	Unmetered bool
+	// GuildID scopes the torrent to a Discord guild, whose settings restrict the category.
+	GuildID string
+}
+
+// size is the total size of the torrent, if the magnet link or .torrent file says.
+func (r Request) size() (uint64, bool) {
+	if len(r.Metainfo) != 0 {
+		metainfo, err := torrent.ParseMetainfo(r.Metainfo)
+		if err != nil || metainfo.Length == 0 {
+			return 0, false
+		}
+		return metainfo.Length, true
+	}
+	return torrent.MagnetURISize(r.MagnetLink)
+}
+
+// Adder is the single path for adding torrents to transmission and recording them in the db.
+type Adder struct {
+	logger *zap.Logger
+	sess   db.Session
+	tx     *tracing.Transmission
+	guard  *DiskGuard
+	quotas *Quotas
+}
+
+func NewAdder(sess db.Session, tx *tracing.Transmission, guard *DiskGuard, quotas *Quotas) *Adder {
+	return &Adder{
+		logger: zap.L(),
+		sess:   sess,
+		tx:     tx,
+		guard:  guard,
+		quotas: quotas,
+	}
+}
+
+// Check validates the request and the user's quota without adding anything.
+func (a *Adder) Check(ctx context.Context, req Request) error {
+	if req.Category != "" {
+		category, err := NormalizeCategory(req.Category)
+		if err != nil {
+			return err
+		}
+		settings, err := models.GetGuildSettings(ctx, a.sess, req.GuildID)
+		if err != nil {
+			return fmt.Errorf("failed to get guild settings: %w", err)
+		}
+		if !settings.AllowsCategory(category) {
+			return categoryNotAllowedError{category}
+		}
+	}
+	if req.RequestedBy != "" && !req.Unmetered {
+		size, _ := req.size()
+		if err := a.quotas.Check(ctx, req.RequestedBy, req.Roles, size); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *Adder) Add(ctx context.Context, req Request) (*models.Torrent, error) {
+	if err := a.Check(ctx, req); err != nil {
+		return nil, err
+	}
+	meta := &models.TorrentMetadata{
+		FriendlyName: req.FriendlyName,
+		RequestedBy:  req.RequestedBy,
+		GuildID:      req.GuildID,
+	}
+	newTorrent := tracing.NewTorrent{
+		MagnetLink: req.MagnetLink,
+		Metainfo:   req.Metainfo,
+	}
+	if req.Category != "" {
+		category, _ := NormalizeCategory(req.Category)
+		newTorrent.SubDir = strings.ToLower(category)
+		meta.Categories = []string{category}
+	}
+	size, sizeKnown := req.size()
+	// Torrents with a known size are checked up front, the rest once the scraper knows their size
+	var diskGuard string
+	if sizeKnown && a.guard.Enabled() {
+		diskGuard = DiskGuardOK
+		if err := a.guard.Check(ctx, size); err != nil {
+			if !IsInsufficientSpace(err) || a.guard.Action() == config.DiskGuardActionReject {
+				return nil, err
+			}
+			diskGuard = DiskGuardPaused + err.Error()
+			// Added paused, so it doesn't start downloading before it can be stopped
+			newTorrent.Paused = true
+		}
+	}
+	torrentID, err := a.tx.AddTorrent(ctx, newTorrent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add torrent: %w", err)
+	}
+	// Scrape new torrent
+	torrents, err := a.tx.GetTorrents(ctx, torrentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get torrents from transmission: %w", err)
+	}
+	a.logger.Debug("scraped torrent from transmission", zap.Int("id", torrentID))
+	if len(torrents) != 1 {
+		return nil, unexpectedNumberOfTorrentsError{
+			want: 1,
+			got:  len(torrents),
+		}
+	}
+	added := models.FromTransmission(torrents[0])
+	meta.Labels = torrent.ParseRelease(added.Name).Labels()
+	if diskGuard != "" {
+		meta.Labels[models.DiskGuardLabel] = diskGuard
+	}
+	if req.RequestedBy != "" && !req.Unmetered && !sizeKnown {
+		// Checked against the quota again once the scraper knows its size
+		meta.Labels[models.QuotaLabel] = QuotaPending + strings.Join(req.Roles, ",")
+	}
+	added.TorrentMetadata = meta
+	if _, err := added.Set(ctx, a.sess); err != nil {
+		return nil, fmt.Errorf("failed to set in db: %w", err)
+	}
+	if req.RecipientID != "" || req.ChannelID != "" {
+		notification := models.TorrentNotification{
+			ID:          uuid.NewString(),
+			TorrentID:   added.ID,
+			RecipientID: req.RecipientID,
+			ChannelID:   req.ChannelID,
+		}
+		if err := notification.Create(ctx, a.sess); err != nil {
+			return nil, fmt.Errorf("failed to create notification: %w", err)
+		}
+	}
+	return added, nil
+}
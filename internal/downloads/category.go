@@ -0,0 +1,98 @@
+package downloads
+
+import (
+	"path"
+	"strings"
+
+	"github.com/bobcob7/polly-bot/internal/torrent"
+)
+
+type fileKind int
+
+const (
+	otherFile fileKind = iota
+	videoFile
+	audioFile
+	audiobookFile
+	ebookFile
+	softwareFile
+)
+
+var fileKinds = map[string]fileKind{
+	".mkv":      videoFile,
+	".mp4":      videoFile,
+	".avi":      videoFile,
+	".m4v":      videoFile,
+	".mov":      videoFile,
+	".wmv":      videoFile,
+	".ts":       videoFile,
+	".mp3":      audioFile,
+	".flac":     audioFile,
+	".ogg":      audioFile,
+	".opus":     audioFile,
+	".wav":      audioFile,
+	".m4a":      audioFile,
+	".m4b":      audiobookFile,
+	".aax":      audiobookFile,
+	".epub":     ebookFile,
+	".mobi":     ebookFile,
+	".azw3":     ebookFile,
+	".pdf":      ebookFile,
+	".cbz":      ebookFile,
+	".cbr":      ebookFile,
+	".exe":      softwareFile,
+	".msi":      softwareFile,
+	".dmg":      softwareFile,
+	".pkg":      softwareFile,
+	".deb":      softwareFile,
+	".rpm":      softwareFile,
+	".appimage": softwareFile,
+	".iso":      softwareFile,
+}
+
+// DetectCategory guesses the category from the kind of files making up most of the torrent's size,
+// using the release name to tell shows from movies. It returns an empty string if nothing stands out.
+func DetectCategory(name string, files []torrent.File) string {
+	release := torrent.ParseRelease(name)
+	sizes := make(map[fileKind]uint64)
+	var total uint64
+	episodes := 0
+	for _, file := range files {
+		kind := fileKinds[strings.ToLower(path.Ext(file.Path))]
+		sizes[kind] += file.Length
+		total += file.Length
+		if kind == videoFile && torrent.ParseRelease(path.Base(file.Path)).FirstEpisode != 0 {
+			episodes++
+		}
+	}
+	if total == 0 {
+		return release.Category()
+	}
+	var dominant fileKind
+	for kind, size := range sizes {
+		if size*2 > total {
+			dominant = kind
+		}
+	}
+	switch dominant {
+	case videoFile:
+		if release.IsEpisode() || episodes > 1 {
+			return "TV SHOW"
+		}
+		return "MOVIE"
+	case audioFile:
+		// Albums rarely include chapterized audiobook files
+		if sizes[audiobookFile] > 0 {
+			return "AUDIOBOOK"
+		}
+		return "MUSIC"
+	case audiobookFile:
+		return "AUDIOBOOK"
+	case ebookFile:
+		return "BOOK"
+	case softwareFile:
+		return "SOFTWARE"
+	default:
+		return release.Category()
+	}
+}
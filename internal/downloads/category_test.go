@@ -0,0 +1,68 @@
+package downloads
+
+import (
+	"testing"
+
+	"github.com/bobcob7/polly-bot/internal/torrent"
+)
+
+func TestDetectCategory(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		name  string
+		files []torrent.File
+		want  string
+	}{
+		"Movie": {
+			name:  "Film.2001.1080p.BluRay.x264-GRP",
+			files: []torrent.File{{Path: "Film/film.mkv", Length: 4000}, {Path: "Film/film.nfo", Length: 1}},
+			want:  "MOVIE",
+		},
+		"Season": {
+			name: "Some Show Complete",
+			files: []torrent.File{
+				{Path: "Show/Show.S01E01.mkv", Length: 1000},
+				{Path: "Show/Show.S01E02.mkv", Length: 1000},
+			},
+			want: "TV SHOW",
+		},
+		"Music": {
+			name:  "Artist - Album (2001) [FLAC]",
+			files: []torrent.File{{Path: "Album/01.flac", Length: 30}, {Path: "Album/cover.jpg", Length: 2}},
+			want:  "MUSIC",
+		},
+		"Audiobook": {
+			name:  "Author - Book",
+			files: []torrent.File{{Path: "Book/Book.m4b", Length: 300}},
+			want:  "AUDIOBOOK",
+		},
+		"Book": {
+			name:  "Author - Book",
+			files: []torrent.File{{Path: "Book.epub", Length: 3}},
+			want:  "BOOK",
+		},
+		"Software": {
+			name:  "distro-22.04-amd64",
+			files: []torrent.File{{Path: "distro-22.04-amd64.iso", Length: 3000}},
+			want:  "SOFTWARE",
+		},
+		"Release name only": {
+			name: "Some.Show.S01E02.1080p.WEB-DL.x264-GRP",
+			want: "TV SHOW",
+		},
+		"Mixed": {
+			name:  "Stuff",
+			files: []torrent.File{{Path: "a.mkv", Length: 10}, {Path: "b.mp3", Length: 10}},
+			want:  "",
+		},
+	}
+	for name, tt := range tests {
+		testData := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if got := DetectCategory(testData.name, testData.files); got != testData.want {
+				t.Errorf("DetectCategory() = %v, want %v", got, testData.want)
+			}
+		})
+	}
+}
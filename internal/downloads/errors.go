@@ -0,0 +1,64 @@
+package downloads
+
+import (
+	"errors"
+	"fmt"
+)
+
+type unexpectedCategoryError struct {
+	category string
+}
+
+func (u unexpectedCategoryError) Error() string {
+	return fmt.Sprintf("unknown category: %q", u.category)
+}
+
+type categoryNotAllowedError struct {
+	category string
+}
+
+func (c categoryNotAllowedError) Error() string {
+	return fmt.Sprintf("category %s isn't allowed in this server", c.category)
+}
+
+type unexpectedNumberOfTorrentsError struct {
+	want int
+	got  int
+}
+
+func (u unexpectedNumberOfTorrentsError) Error() string {
+	return fmt.Sprintf("scraped %d torrents intead of %d", u.got, u.want)
+}
+
+const mebibyte = 1024 * 1024
+
+type insufficientSpaceError struct {
+	required uint64
+	free     uint64
+	reserve  uint64
+}
+
+func (i insufficientSpaceError) Error() string {
+	return fmt.Sprintf("not enough disk space: need %d MiB with %d MiB free and %d MiB reserved",
+		i.required/mebibyte, i.free/mebibyte, i.reserve/mebibyte)
+}
+
+// IsInsufficientSpace returns whether the error is from a torrent not fitting on the disk.
+func IsInsufficientSpace(err error) bool {
+	return errors.As(err, &insufficientSpaceError{})
+}
+
+var errQuotaBlocked = errors.New("you are blocked from adding downloads")
+
+// IsQuotaExceeded reports whether the error is because the user's quota doesn't allow the download.
+func IsQuotaExceeded(err error) bool {
+	return errors.As(err, &quotaExceededError{}) || errors.Is(err, errQuotaBlocked)
+}
+
+type quotaExceededError struct {
+	usage string
+}
+
+func (q quotaExceededError) Error() string {
+	return fmt.Sprintf("download quota exceeded: already used %s", q.usage)
+}
@@ -0,0 +1,65 @@
+package downloads
+
+import (
+	"context"
+
+	"github.com/bobcob7/polly-bot/internal/config"
+)
+
+// Values of models.DiskGuardLabel, paused and rejected are followed by the reason.
+const (
+	DiskGuardOK       = "ok"
+	DiskGuardPaused   = "paused: "
+	DiskGuardRejected = "rejected: "
+)
+
+// Values of models.QuotaLabel, pending is followed by the requester's comma separated roles
+// and rejected by the reason.
+const (
+	QuotaOK       = "ok"
+	QuotaPending  = "pending: "
+	QuotaRejected = "rejected: "
+)
+
+// DiskGuard checks whether a torrent fits into transmission's download directory while keeping the reserve free.
+type DiskGuard struct {
+	config    config.TransmissionDiskGuard
+	freeSpace func(ctx context.Context) (uint64, error)
+}
+
+// NewDiskGuard checks against the free space reported by freeSpace, which must come from
+// transmission since the download directory may not be on the same host as Polly.
+func NewDiskGuard(cfg config.TransmissionDiskGuard, freeSpace func(ctx context.Context) (uint64, error)) *DiskGuard {
+	return &DiskGuard{
+		config:    cfg,
+		freeSpace: freeSpace,
+	}
+}
+
+func (g *DiskGuard) Enabled() bool {
+	return g != nil && g.config.Enabled
+}
+
+// Action is what to do with torrents which don't fit.
+func (g *DiskGuard) Action() string {
+	return g.config.Action
+}
+
+// Check returns an insufficientSpaceError if downloading the remaining bytes would eat into the reserve.
+func (g *DiskGuard) Check(ctx context.Context, remaining uint64) error {
+	if !g.Enabled() {
+		return nil
+	}
+	free, err := g.freeSpace(ctx)
+	if err != nil {
+		return err
+	}
+	if free < g.config.Reserve || free-g.config.Reserve < remaining {
+		return insufficientSpaceError{
+			required: remaining,
+			free:     free,
+			reserve:  g.config.Reserve,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package downloads
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bobcob7/polly-bot/internal/config"
+)
+
+func TestDiskGuard_Check(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		enabled   bool
+		remaining uint64
+		wantErr   bool
+	}{
+		"Fits": {
+			enabled:   true,
+			remaining: 50,
+		},
+		"Eats into reserve": {
+			enabled:   true,
+			remaining: 80,
+			wantErr:   true,
+		},
+		"Disabled": {
+			remaining: 200,
+		},
+	}
+	for name, tt := range tests {
+		testData := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			guard := &DiskGuard{
+				config: config.TransmissionDiskGuard{Enabled: testData.enabled, Reserve: 30},
+				freeSpace: func(context.Context) (uint64, error) {
+					return 100, nil
+				},
+			}
+			err := guard.Check(context.Background(), testData.remaining)
+			if (err != nil) != testData.wantErr {
+				t.Errorf("DiskGuard.Check() error = %v, wantErr %v", err, testData.wantErr)
+			}
+			if err != nil && !IsInsufficientSpace(err) {
+				t.Errorf("DiskGuard.Check() error = %v, want insufficient space", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package downloads
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bobcob7/polly-bot/internal/config"
+	"github.com/bobcob7/polly-bot/internal/models"
+	"github.com/upper/db/v4"
+)
+
+// Quotas enforces how much each user can download.
+type Quotas struct {
+	config config.Quotas
+	access config.Access
+	sess   db.Session
+}
+
+func NewQuotas(cfg config.Quotas, access config.Access, sess db.Session) *Quotas {
+	return &Quotas{
+		config: cfg,
+		access: access,
+		sess:   sess,
+	}
+}
+
+// Limit returns the user's quota, preferring their override to the most generous quota of their roles.
+// The bool is false for admins, who are unlimited.
+func (q *Quotas) Limit(ctx context.Context, userID string, roles []string) (config.Quota, bool, error) {
+	if q.access.IsAdmin(userID, roles) {
+		return config.Quota{}, false, nil
+	}
+	override, err := models.GetUserQuota(ctx, q.sess, userID)
+	if err != nil {
+		return config.Quota{}, false, fmt.Errorf("failed getting quota override: %w", err)
+	}
+	if override != nil {
+		return config.Quota{
+			MaxActive:   override.MaxActive,
+			DailyBytes:  override.DailyBytes,
+			WeeklyBytes: override.WeeklyBytes,
+			Blocked:     override.Blocked,
+		}, true, nil
+	}
+	limit := q.config.Default
+	matched := false
+	for _, roleQuota := range q.config.Roles {
+		for _, role := range roles {
+			if role != roleQuota.RoleID {
+				continue
+			}
+			if !matched {
+				limit, matched = roleQuota.Quota, true
+			} else {
+				limit = mostGenerous(limit, roleQuota.Quota)
+			}
+		}
+	}
+	return limit, true, nil
+}
+
+// mostGenerous combines two quotas into one with the higher of each limit, where zero is unlimited.
+// The combined quota is only blocked if both are.
+func mostGenerous(a, b config.Quota) config.Quota {
+	if a.Blocked {
+		return b
+	}
+	if b.Blocked {
+		return a
+	}
+	if a.MaxActive != 0 && (b.MaxActive == 0 || b.MaxActive > a.MaxActive) {
+		a.MaxActive = b.MaxActive
+	}
+	if a.DailyBytes != 0 && (b.DailyBytes == 0 || b.DailyBytes > a.DailyBytes) {
+		a.DailyBytes = b.DailyBytes
+	}
+	if a.WeeklyBytes != 0 && (b.WeeklyBytes == 0 || b.WeeklyBytes > a.WeeklyBytes) {
+		a.WeeklyBytes = b.WeeklyBytes
+	}
+	return a
+}
+
+// Check returns a quotaExceededError, or errQuotaBlocked, if adding a torrent of the size would exceed the user's quota.
+// Torrents of unknown size are checked again by Recheck once their size is known.
+func (q *Quotas) Check(ctx context.Context, userID string, roles []string, size uint64) error {
+	limit, limited, err := q.Limit(ctx, userID, roles)
+	if err != nil || !limited {
+		return err
+	}
+	usage, err := models.GetUserUsage(ctx, q.sess, userID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed getting quota usage: %w", err)
+	}
+	return exceeded(limit, usage, size)
+}
+
+// Recheck returns a quotaExceededError if the user's torrents, including one whose size just became known,
+// are over their byte limits.
+func (q *Quotas) Recheck(ctx context.Context, userID string, roles []string) error {
+	limit, limited, err := q.Limit(ctx, userID, roles)
+	if err != nil || !limited {
+		return err
+	}
+	usage, err := models.GetUserUsage(ctx, q.sess, userID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed getting quota usage: %w", err)
+	}
+	return exceededBytes(limit, usage, 0)
+}
+
+func exceeded(limit config.Quota, usage models.UserUsage, size uint64) error {
+	if limit.Blocked {
+		return errQuotaBlocked
+	}
+	if limit.MaxActive != 0 && usage.Active >= limit.MaxActive {
+		return quotaExceededError{fmt.Sprintf("%d of %d active downloads", usage.Active, limit.MaxActive)}
+	}
+	return exceededBytes(limit, usage, size)
+}
+
+func exceededBytes(limit config.Quota, usage models.UserUsage, size uint64) error {
+	if limit.DailyBytes != 0 && usage.DailyBytes+size > limit.DailyBytes {
+		return quotaExceededError{fmt.Sprintf("%d of %d MiB today", usage.DailyBytes/mebibyte, limit.DailyBytes/mebibyte)}
+	}
+	if limit.WeeklyBytes != 0 && usage.WeeklyBytes+size > limit.WeeklyBytes {
+		return quotaExceededError{fmt.Sprintf("%d of %d MiB this week", usage.WeeklyBytes/mebibyte, limit.WeeklyBytes/mebibyte)}
+	}
+	return nil
+}
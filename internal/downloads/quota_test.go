@@ -0,0 +1,78 @@
+package downloads
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bobcob7/polly-bot/internal/config"
+	"github.com/bobcob7/polly-bot/internal/models"
+)
+
+func TestMostGenerous(t *testing.T) {
+	t.Parallel()
+	got := mostGenerous(
+		config.Quota{MaxActive: 2, DailyBytes: 10, WeeklyBytes: 0},
+		config.Quota{MaxActive: 1, DailyBytes: 0, WeeklyBytes: 50},
+	)
+	want := config.Quota{MaxActive: 2, DailyBytes: 0, WeeklyBytes: 0}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("mostGenerous() = %+v, want %+v", got, want)
+	}
+	got = mostGenerous(config.Quota{Blocked: true}, config.Quota{MaxActive: 1})
+	want = config.Quota{MaxActive: 1}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("mostGenerous() = %+v, want %+v", got, want)
+	}
+}
+
+func TestExceeded(t *testing.T) {
+	t.Parallel()
+	limit := config.Quota{MaxActive: 2, DailyBytes: 100, WeeklyBytes: 300}
+	tests := map[string]struct {
+		limit   *config.Quota
+		usage   models.UserUsage
+		size    uint64
+		wantErr bool
+	}{
+		"Within quota": {
+			usage: models.UserUsage{Active: 1, DailyBytes: 50, WeeklyBytes: 50},
+			size:  50,
+		},
+		"Too many active": {
+			usage:   models.UserUsage{Active: 2},
+			wantErr: true,
+		},
+		"Daily bytes": {
+			usage:   models.UserUsage{DailyBytes: 60, WeeklyBytes: 60},
+			size:    50,
+			wantErr: true,
+		},
+		"Weekly bytes": {
+			usage:   models.UserUsage{WeeklyBytes: 299},
+			size:    2,
+			wantErr: true,
+		},
+		"Unlimited": {
+			limit: &config.Quota{},
+			usage: models.UserUsage{Active: 20, DailyBytes: 1000, WeeklyBytes: 1000},
+			size:  50,
+		},
+		"Blocked": {
+			limit:   &config.Quota{Blocked: true},
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		testData := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			limit := limit
+			if testData.limit != nil {
+				limit = *testData.limit
+			}
+			if err := exceeded(limit, testData.usage, testData.size); (err != nil) != testData.wantErr {
+				t.Errorf("exceeded() error = %v, wantErr %v", err, testData.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/upper/db/v4"
+)
+
+// Scraper is when transmission was last scraped successfully.
+type Scraper interface {
+	LastScrape() time.Time
+}
+
+// ScrapeCheck fails once the last successful scrape is older than maxAge.
+// It's only checked for readiness, since scrapes also fail while transmission is down
+// and restarting Polly wouldn't help.
+func ScrapeCheck(scraper Scraper, maxAge time.Duration) Check {
+	return Check{
+		Name: "scrape",
+		Check: func(ctx context.Context) (string, error) {
+			age := time.Since(scraper.LastScrape()).Truncate(time.Second)
+			detail := fmt.Sprintf("last scraped %s ago", age)
+			if age > maxAge {
+				return detail, staleScrapeError{age: age, maxAge: maxAge}
+			}
+			return detail, nil
+		},
+	}
+}
+
+// Gateway is whether the Discord gateway is connected.
+type Gateway interface {
+	Connected() bool
+}
+
+// DiscordCheck fails while the gateway is disconnected, the session reconnects on its own.
+func DiscordCheck(gateway Gateway) Check {
+	return Check{
+		Name: "discord",
+		Check: func(ctx context.Context) (string, error) {
+			if !gateway.Connected() {
+				return "", errDisconnected
+			}
+			return "connected", nil
+		},
+	}
+}
+
+// migrationsTableName is where golang-migrate records the schema version.
+const migrationsTableName = "schema_migrations"
+
+// DatabaseCheck fails if the database can't be reached or the last migration didn't finish.
+func DatabaseCheck(sess db.Session) Check {
+	return Check{
+		Name: "database",
+		Check: func(ctx context.Context) (string, error) {
+			if err := sess.WithContext(ctx).Ping(); err != nil {
+				return "", fmt.Errorf("failed pinging database: %w", err)
+			}
+			var (
+				version int64
+				dirty   bool
+			)
+			row, err := sess.SQL().QueryRowContext(ctx, "SELECT version, dirty FROM "+migrationsTableName+" LIMIT 1")
+			if err != nil {
+				return "", fmt.Errorf("failed querying migration version: %w", err)
+			}
+			if err := row.Scan(&version, &dirty); err != nil {
+				return "", fmt.Errorf("failed reading migration version: %w", err)
+			}
+			detail := fmt.Sprintf("migration version %d", version)
+			if dirty {
+				return detail, dirtyMigrationError{version: version}
+			}
+			return detail, nil
+		},
+	}
+}
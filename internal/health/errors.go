@@ -0,0 +1,29 @@
+package health
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	errDisconnected   = errors.New("discord gateway is disconnected")
+	errUnknownService = errors.New("unknown service")
+)
+
+type staleScrapeError struct {
+	age    time.Duration
+	maxAge time.Duration
+}
+
+func (s staleScrapeError) Error() string {
+	return fmt.Sprintf("last successful scrape was %s ago, more than %s", s.age, s.maxAge)
+}
+
+type dirtyMigrationError struct {
+	version int64
+}
+
+func (d dirtyMigrationError) Error() string {
+	return fmt.Sprintf("migration %d is dirty", d.version)
+}
@@ -0,0 +1,198 @@
+// Package health reports whether Polly and the services it depends on are working,
+// over HTTP for Kubernetes probes and over the gRPC health protocol.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	healthv1 "github.com/bobcob7/polly-bot/pkg/proto/grpc/health/v1"
+	"github.com/bobcob7/polly-bot/pkg/proto/grpc/health/v1/healthv1connect"
+	"github.com/bufbuild/connect-go"
+	"go.uber.org/zap"
+)
+
+// Check is a named dependency check, returning details about the dependency's state.
+type Check struct {
+	Name string
+	// Live checks fail liveness as well as readiness, so the pod is restarted while they fail.
+	// They should only fail when restarting could help.
+	Live  bool
+	Check func(ctx context.Context) (string, error)
+}
+
+type Checker struct {
+	healthv1connect.UnimplementedHealthHandler
+
+	logger       *zap.Logger
+	timeout      time.Duration
+	watchPeriod  time.Duration
+	checks       []Check
+	checksLock   sync.RWMutex
+	serviceNames map[string]bool
+}
+
+var _ healthv1connect.HealthHandler = &Checker{}
+
+// NewChecker limits each check to timeout, services are the gRPC service names it reports for,
+// besides the empty name for the whole server.
+func NewChecker(timeout time.Duration, services ...string) *Checker {
+	serviceNames := make(map[string]bool, len(services)+1)
+	serviceNames[""] = true
+	for _, service := range services {
+		serviceNames[service] = true
+	}
+	return &Checker{
+		logger:       zap.L(),
+		timeout:      timeout,
+		watchPeriod:  5 * time.Second,
+		serviceNames: serviceNames,
+	}
+}
+
+func (c *Checker) Register(checks ...Check) {
+	c.checksLock.Lock()
+	defer c.checksLock.Unlock()
+	c.checks = append(c.checks, checks...)
+}
+
+// Result is the outcome of a single check.
+type Result struct {
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Report is the outcome of every check that was run.
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+func (r Report) OK() bool {
+	return r.Status == StatusOK
+}
+
+// Run runs every check concurrently, or only the live ones.
+func (c *Checker) Run(ctx context.Context, liveOnly bool) Report {
+	c.checksLock.RLock()
+	checks := make([]Check, 0, len(c.checks))
+	for _, check := range c.checks {
+		if check.Live || !liveOnly {
+			checks = append(checks, check)
+		}
+	}
+	c.checksLock.RUnlock()
+	report := Report{
+		Status: StatusOK,
+		Checks: make(map[string]Result, len(checks)),
+	}
+	var (
+		wg         sync.WaitGroup
+		reportLock sync.Mutex
+	)
+	for _, check := range checks {
+		check := check
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := c.run(ctx, check)
+			reportLock.Lock()
+			defer reportLock.Unlock()
+			report.Checks[check.Name] = result
+			if result.Status != StatusOK {
+				report.Status = StatusFail
+			}
+		}()
+	}
+	wg.Wait()
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, check Check) Result {
+	checkCtx, done := context.WithTimeout(ctx, c.timeout)
+	defer done()
+	detail, err := check.Check(checkCtx)
+	if err != nil {
+		return Result{Status: StatusFail, Detail: detail, Error: err.Error()}
+	}
+	return Result{Status: StatusOK, Detail: detail}
+}
+
+// Liveness serves the live checks, for /healthz.
+func (c *Checker) Liveness() http.Handler {
+	return c.handler(true)
+}
+
+// Readiness serves every check, for /readyz.
+func (c *Checker) Readiness() http.Handler {
+	return c.handler(false)
+}
+
+func (c *Checker) handler(liveOnly bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := c.Run(r.Context(), liveOnly)
+		if !report.OK() {
+			c.logger.Warn("health check failed", zap.Bool("liveOnly", liveOnly), zap.Any("checks", report.Checks))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		if report.OK() {
+			w.WriteHeader(http.StatusOK)
+		} else {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		if err := json.NewEncoder(w).Encode(report); err != nil {
+			c.logger.Error("failed to write health report", zap.Error(err))
+		}
+	})
+}
+
+func (c *Checker) status(ctx context.Context, service string) (healthv1.HealthCheckResponse_ServingStatus, error) {
+	if !c.serviceNames[service] {
+		return healthv1.HealthCheckResponse_SERVICE_UNKNOWN, errUnknownService
+	}
+	if !c.Run(ctx, false).OK() {
+		return healthv1.HealthCheckResponse_NOT_SERVING, nil
+	}
+	return healthv1.HealthCheckResponse_SERVING, nil
+}
+
+// Check implements the gRPC health protocol, a service is serving while every check passes.
+func (c *Checker) Check(ctx context.Context, req *connect.Request[healthv1.HealthCheckRequest]) (*connect.Response[healthv1.HealthCheckResponse], error) {
+	status, err := c.status(ctx, req.Msg.Service)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeNotFound, err)
+	}
+	return connect.NewResponse(&healthv1.HealthCheckResponse{Status: status}), nil
+}
+
+// Watch sends the service's status, then again whenever it changes.
+func (c *Checker) Watch(ctx context.Context, req *connect.Request[healthv1.HealthCheckRequest], stream *connect.ServerStream[healthv1.HealthCheckResponse]) error {
+	ticker := time.NewTicker(c.watchPeriod)
+	defer ticker.Stop()
+	last := healthv1.HealthCheckResponse_UNKNOWN
+	for {
+		// Unknown services are reported rather than failed, so clients can wait for them
+		status, _ := c.status(ctx, req.Msg.Service)
+		if status != last {
+			if err := stream.Send(&healthv1.HealthCheckResponse{Status: status}); err != nil {
+				return err
+			}
+			last = status
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
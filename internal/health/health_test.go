@@ -0,0 +1,76 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var errTest = errors.New("test")
+
+func TestChecker_handler(t *testing.T) {
+	t.Parallel()
+	passing := Check{
+		Name:  "passing",
+		Live:  true,
+		Check: func(ctx context.Context) (string, error) { return "", nil },
+	}
+	failing := Check{
+		Name:  "failing",
+		Check: func(ctx context.Context) (string, error) { return "", errTest },
+	}
+	failingLive := Check{
+		Name:  "failingLive",
+		Live:  true,
+		Check: func(ctx context.Context) (string, error) { return "", errTest },
+	}
+	tests := map[string]struct {
+		checks    []Check
+		liveness  int
+		readiness int
+	}{
+		"No checks": {
+			liveness:  http.StatusOK,
+			readiness: http.StatusOK,
+		},
+		"Passing": {
+			checks:    []Check{passing},
+			liveness:  http.StatusOK,
+			readiness: http.StatusOK,
+		},
+		"Failing readiness": {
+			checks:    []Check{passing, failing},
+			liveness:  http.StatusOK,
+			readiness: http.StatusServiceUnavailable,
+		},
+		"Failing liveness": {
+			checks:    []Check{passing, failingLive},
+			liveness:  http.StatusServiceUnavailable,
+			readiness: http.StatusServiceUnavailable,
+		},
+	}
+	for name, tt := range tests {
+		testData := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			checker := NewChecker(time.Second)
+			checker.Register(testData.checks...)
+			for handlerName, handler := range map[string]struct {
+				handler http.Handler
+				want    int
+			}{
+				"Liveness":  {checker.Liveness(), testData.liveness},
+				"Readiness": {checker.Readiness(), testData.readiness},
+			} {
+				rec := httptest.NewRecorder()
+				handler.handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+				if rec.Code != handler.want {
+					t.Errorf("%s() status = %d, want %d", handlerName, rec.Code, handler.want)
+				}
+			}
+		})
+	}
+}
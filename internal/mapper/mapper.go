@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"time"
 )
 
 type DecoderOption func(d *Decoder)
@@ -81,29 +82,31 @@ func (d *Decoder) decode(root string, v reflect.Value) (bool, error) {
 			if root != "" {
 				subKey = root + d.separator + subKey
 			}
+			// A struct is found if any of its fields are found
+			var fieldFound bool
 			switch f.Kind() {
 			case reflect.Struct:
-				if found, err = d.decode(subKey, f); err != nil {
+				if fieldFound, err = d.decode(subKey, f); err != nil {
 					return false, err
 				}
 			case reflect.Slice:
-				if found, err = d.decode(subKey, f); err != nil {
+				if fieldFound, err = d.decode(subKey, f); err != nil {
 					return false, err
 				}
 			case reflect.Ptr:
 				value := reflect.New(f.Type().Elem())
-				found, err := d.decode(subKey, value)
-				if err != nil {
+				if fieldFound, err = d.decode(subKey, value); err != nil {
 					return false, err
 				}
-				if found {
+				if fieldFound {
 					f.Set(value)
 				}
 			default:
-				if found, err = d.decodePrimitive(subKey, f); err != nil {
+				if fieldFound, err = d.decodePrimitive(subKey, f); err != nil {
 					return false, err
 				}
 			}
+			found = found || fieldFound
 		}
 	case reflect.Slice:
 		sliceType := v.Type().Elem()
@@ -138,11 +141,21 @@ func (d *Decoder) decode(root string, v reflect.Value) (bool, error) {
 	return found, nil
 }
 
+var durationType = reflect.TypeOf(time.Duration(0))
+
 func (d *Decoder) decodePrimitive(key string, v reflect.Value) (bool, error) {
 	value, found := d.lookup(key)
 	if !found {
 		return false, nil
 	}
+	if v.Type() == durationType {
+		decodedValue, err := time.ParseDuration(value)
+		if err != nil {
+			return false, fmt.Errorf("failed parsing duration: %w", err)
+		}
+		v.SetInt(int64(decodedValue))
+		return true, nil
+	}
 	switch v.Kind() {
 	case reflect.Bool:
 		decodedValue, err := strconv.ParseBool(value)
@@ -5,6 +5,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/bobcob7/polly-bot/internal/mapper"
 	"github.com/go-test/deep"
@@ -17,6 +18,7 @@ import (
 // - floats
 // - complex
 // - strings
+// - durations
 // - bytes
 // - slices
 // - structs
@@ -63,6 +65,7 @@ func Test_Decoder(t *testing.T) {
 		Complex64     complex64      `map:"complex64"`
 		Complex128    complex128     `map:"complex128"`
 		String        string         `map:"string"`
+		Duration      time.Duration  `map:"duration"`
 		Bytes         []byte         `map:"bytes"`
 		Slice         []string       `map:"slice"`
 		Struct        testSubStruct  `map:"struct"`
@@ -87,6 +90,7 @@ func Test_Decoder(t *testing.T) {
 		Complex64:     64i,
 		Complex128:    128i,
 		String:        "string",
+		Duration:      90 * time.Second,
 		Struct:        testSubStruct{String: "struct_string"},
 		Slice:         []string{"1", "2", "3"},
 		Bytes:         []byte{48, 49, 50, 51, 52},
@@ -111,6 +115,7 @@ func Test_Decoder(t *testing.T) {
 			"complex64":             "64i",
 			"complex128":            "128i",
 			"string":                "string",
+			"duration":              "1m30s",
 			"struct_string":         "struct_string",
 			"slice_0":               "1",
 			"slice_1":               "2",
@@ -198,3 +203,33 @@ func Test_Cool_Separator(t *testing.T) {
 		t.Error(diff)
 	}
 }
+
+func Test_Slice_Of_Structs(t *testing.T) {
+	t.Parallel()
+	type testSubStruct struct {
+		Name  string `map:"name"`
+		Count int    `map:"count"`
+	}
+	type testStruct struct {
+		Slice []testSubStruct `map:"slice"`
+	}
+	var got testStruct
+	want := testStruct{
+		Slice: []testSubStruct{
+			{Name: "first", Count: 1},
+			{Name: "second"},
+		},
+	}
+	dec := mapper.NewDecoder(mapper.MapLookup(
+		map[string]string{
+			"slice_0_name":  "first",
+			"slice_0_count": "1",
+			"slice_1_name":  "second",
+		}))
+	err := dec.Decode(&got)
+	if err != nil {
+		t.Error("error decoding", err)
+	} else if diff := deep.Equal(got, want); diff != nil {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,128 @@
+// Package metrics holds the Prometheus collectors served on /metrics.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "polly"
+
+var (
+	// CommandInvocations counts handled Discord interactions by command and result.
+	CommandInvocations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "command_invocations_total",
+		Help:      "Discord interactions handled, by command and result.",
+	}, []string{"command", "result"})
+	CommandDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "command_duration_seconds",
+		Help:      "Time taken to handle Discord interactions, by command.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"command"})
+
+	ScrapeDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "scrape_duration_seconds",
+		Help:      "Time taken to scrape transmission.",
+		Buckets:   prometheus.DefBuckets,
+	})
+	ScrapeFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "scrape_failures_total",
+		Help:      "Failed scrapes of transmission.",
+	})
+	ScrapePeriod = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "scrape_period_seconds",
+		Help:      "Current time between scrapes, it backs off while scrapes fail.",
+	})
+
+	// Torrents is the number of torrents in transmission by status, as of the last scrape.
+	Torrents = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "torrents",
+		Help:      "Torrents in transmission, by status.",
+	}, []string{"status"})
+	DownloadedBytes = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "downloaded_bytes",
+		Help:      "Bytes downloaded by the torrents in transmission.",
+	})
+	UploadedBytes = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "uploaded_bytes",
+		Help:      "Bytes uploaded by the torrents in transmission.",
+	})
+
+	// Notifications counts notifications by where they were sent and whether sending failed.
+	Notifications = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "notifications_total",
+		Help:      "Notifications sent, by target and result.",
+	}, []string{"target", "result"})
+
+	RPCRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rpc_requests_total",
+		Help:      "RPCs handled, by procedure and code.",
+	}, []string{"procedure", "code"})
+	RPCDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "rpc_duration_seconds",
+		Help:      "Time taken to handle RPCs, by procedure.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"procedure"})
+)
+
+const (
+	NotificationSent   = "sent"
+	NotificationFailed = "failed"
+)
+
+// Commands records the Discord interactions the bot handled.
+type Commands struct{}
+
+func (Commands) ObserveCommand(command, result string, duration time.Duration) {
+	CommandInvocations.WithLabelValues(command, result).Inc()
+	CommandDuration.WithLabelValues(command).Observe(duration.Seconds())
+}
+
+// Notification records a notification sent to target, failed if err isn't nil.
+func Notification(target string, err error) {
+	result := NotificationSent
+	if err != nil {
+		result = NotificationFailed
+	}
+	Notifications.WithLabelValues(target, result).Inc()
+}
+
+// Handler serves every registered collector.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// Interceptor records the count, code and latency of every unary RPC.
+func Interceptor() connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			start := time.Now()
+			res, err := next(ctx, req)
+			code := "ok"
+			if err != nil {
+				code = connect.CodeOf(err).String()
+			}
+			procedure := req.Spec().Procedure
+			RPCRequests.WithLabelValues(procedure, code).Inc()
+			RPCDuration.WithLabelValues(procedure).Observe(time.Since(start).Seconds())
+			return res, err
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package models
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/upper/db/v4"
+)
+
+const apiKeysTableName = "api_keys"
+
+// apiKeyPrefix starts every API key, so leaked keys are easy to search for.
+const apiKeyPrefix = "polly_"
+
+// API key scopes, each one includes the scopes before it.
+const (
+	ScopeRead  = "read"
+	ScopeWrite = "write"
+	ScopeAdmin = "admin"
+)
+
+var scopeLevels = map[string]int{
+	ScopeRead:  1,
+	ScopeWrite: 2,
+	ScopeAdmin: 3,
+}
+
+// ValidScope reports whether scope is one of read, write or admin.
+func ValidScope(scope string) bool {
+	_, ok := scopeLevels[scope]
+	return ok
+}
+
+// ScopesAllow reports whether any of the granted scopes includes the required one.
+func ScopesAllow(granted []string, required string) bool {
+	for _, scope := range granted {
+		if scopeLevels[scope] >= scopeLevels[required] {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKey lets a client call RPCs, only a hash of its secret is stored.
+type APIKey struct {
+	ID         string `db:"id"`
+	Name       string `db:"name"`
+	SecretHash string `db:"secret_hash"`
+	// Scopes are the comma separated scopes granted to the key.
+	Scopes string `db:"scopes"`
+	// RateLimit is how many RPCs per minute the key can make, zero uses the configured default.
+	RateLimit  int        `db:"rate_limit"`
+	CreatedBy  string     `db:"created_by"`
+	CreatedAt  time.Time  `db:"created_at"`
+	LastUsedAt *time.Time `db:"last_used_at"`
+	RevokedAt  *time.Time `db:"revoked_at"`
+}
+
+// NewAPIKey generates a key, the returned token is the only time its secret is available.
+func NewAPIKey(name string, scopes []string, rateLimit int, createdBy string) (*APIKey, string, error) {
+	id, err := randomHex(8)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed generating api key id: %w", err)
+	}
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed generating api key secret: %w", err)
+	}
+	key := &APIKey{
+		ID:         id,
+		Name:       name,
+		SecretHash: hashSecret(secret),
+		Scopes:     strings.Join(scopes, ","),
+		RateLimit:  rateLimit,
+		CreatedBy:  createdBy,
+	}
+	return key, apiKeyPrefix + id + "." + secret, nil
+}
+
+// ParseAPIKey splits a token into the key ID and secret.
+func ParseAPIKey(token string) (id, secret string, ok bool) {
+	token, ok = strings.CutPrefix(token, apiKeyPrefix)
+	if !ok {
+		return "", "", false
+	}
+	id, secret, ok = strings.Cut(token, ".")
+	if !ok || id == "" || secret == "" {
+		return "", "", false
+	}
+	return id, secret, true
+}
+
+func randomHex(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// Secrets are random, so a plain hash is enough to make a leaked table useless.
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// Verify reports whether secret belongs to the key.
+func (k *APIKey) Verify(secret string) bool {
+	return subtle.ConstantTimeCompare([]byte(k.SecretHash), []byte(hashSecret(secret))) == 1
+}
+
+func (k *APIKey) ScopeList() []string {
+	return splitList(k.Scopes)
+}
+
+func (k *APIKey) Revoked() bool {
+	return k.RevokedAt != nil
+}
+
+func (k *APIKey) String() string {
+	output := fmt.Sprintf("%s %s (%s)", k.ID, k.Name, k.Scopes)
+	if k.Revoked() {
+		output += " revoked"
+	} else if k.LastUsedAt != nil {
+		output += " last used " + k.LastUsedAt.Format(time.RFC3339)
+	}
+	return output
+}
+
+func (k *APIKey) Create(ctx context.Context, sess db.Session) error {
+	k.CreatedAt = time.Now().UTC()
+	if err := sess.Collection(apiKeysTableName).InsertReturning(k); err != nil {
+		return fmt.Errorf("failed creating api key: %w", err)
+	}
+	return nil
+}
+
+func GetAPIKey(ctx context.Context, sess db.Session, id string) (*APIKey, error) {
+	var output APIKey
+	if err := sess.Collection(apiKeysTableName).Find("id", id).One(&output); err != nil {
+		return nil, fmt.Errorf("failed getting api key: %w", err)
+	}
+	return &output, nil
+}
+
+func GetAPIKeys(ctx context.Context, sess db.Session) ([]*APIKey, error) {
+	output := make([]*APIKey, 0)
+	if err := sess.Collection(apiKeysTableName).Find().OrderBy("created_at").All(&output); err != nil {
+		return nil, fmt.Errorf("failed getting api keys: %w", err)
+	}
+	return output, nil
+}
+
+// Used records when the key was last used.
+func (k *APIKey) Used(ctx context.Context, sess db.Session) error {
+	now := time.Now().UTC()
+	k.LastUsedAt = &now
+	if err := sess.Collection(apiKeysTableName).Find("id", k.ID).Update(map[string]interface{}{"last_used_at": now}); err != nil {
+		return fmt.Errorf("failed updating api key: %w", err)
+	}
+	return nil
+}
+
+func (k *APIKey) Revoke(ctx context.Context, sess db.Session) error {
+	now := time.Now().UTC()
+	k.RevokedAt = &now
+	if err := sess.Collection(apiKeysTableName).Find("id", k.ID).Update(map[string]interface{}{"revoked_at": now}); err != nil {
+		return fmt.Errorf("failed revoking api key: %w", err)
+	}
+	return nil
+}
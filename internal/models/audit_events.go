@@ -0,0 +1,68 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/upper/db/v4"
+)
+
+const auditEventsTableName = "audit_events"
+
+const (
+	AuditSourceDiscord = "discord"
+	AuditSourceRPC     = "rpc"
+)
+
+const (
+	AuditResultOK    = "ok"
+	AuditResultError = "error"
+	AuditResultPanic = "panic"
+)
+
+// AuditEvent records a command, modal, component or RPC someone ran and how it went.
+type AuditEvent struct {
+	ID      int64  `db:"id,omitempty"`
+	Source  string `db:"source"`
+	UserID  string `db:"user_id"`
+	GuildID string `db:"guild_id"`
+	// Action is the command name, custom ID or RPC procedure.
+	Action string `db:"action"`
+	// Options are the JSON encoded command options or RPC request.
+	Options   string    `db:"options"`
+	Result    string    `db:"result"`
+	Error     string    `db:"error"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func (e *AuditEvent) String() string {
+	user := e.UserID
+	if e.Source == AuditSourceDiscord {
+		user = fmt.Sprintf("<@%s>", e.UserID)
+	}
+	output := fmt.Sprintf("%s %s %s %s %s", e.CreatedAt.Format(time.RFC3339), e.Source, user, e.Action, e.Result)
+	if e.Error != "" {
+		output += ": " + e.Error
+	}
+	return output
+}
+
+func (e *AuditEvent) Create(ctx context.Context, sess db.Session) error {
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now().UTC()
+	}
+	if err := sess.Collection(auditEventsTableName).InsertReturning(e); err != nil {
+		return fmt.Errorf("failed creating audit event: %w", err)
+	}
+	return nil
+}
+
+// GetAuditEvents returns the most recent audit events matching the conditions, newest first.
+func GetAuditEvents(ctx context.Context, sess db.Session, cond db.Cond, limit int) ([]*AuditEvent, error) {
+	output := make([]*AuditEvent, 0)
+	if err := sess.Collection(auditEventsTableName).Find(cond).OrderBy("-created_at").Limit(limit).All(&output); err != nil {
+		return nil, fmt.Errorf("failed getting audit events: %w", err)
+	}
+	return output, nil
+}
@@ -0,0 +1,122 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/upper/db/v4"
+)
+
+const downloadRequestsTableName = "download_requests"
+
+const (
+	DownloadRequestPending  = "pending"
+	DownloadRequestApproved = "approved"
+	DownloadRequestDenied   = "denied"
+)
+
+// DownloadRequest is a torrent a non admin asked to add, waiting for an admin to approve it.
+type DownloadRequest struct {
+	ID           string `db:"id"`
+	MagnetLink   string `db:"magnet_link"`
+	FriendlyName string `db:"friendly_name"`
+	Category     string `db:"category"`
+	RequestedBy  string `db:"requested_by"`
+	// RequesterRoles are comma separated, used for the requester's quota once approved.
+	RequesterRoles string     `db:"requester_roles"`
+	ChannelID      string     `db:"channel_id"`
+	GuildID        string     `db:"guild_id"`
+	Status         string     `db:"status"`
+	DecidedBy      *string    `db:"decided_by"`
+	DecidedAt      *time.Time `db:"decided_at"`
+	TorrentID      *string    `db:"torrent_id"`
+	CreatedAt      time.Time  `db:"created_at"`
+}
+
+func (r *DownloadRequest) Roles() []string {
+	return splitList(r.RequesterRoles)
+}
+
+func (r *DownloadRequest) Create(ctx context.Context, sess db.Session) error {
+	r.Status = DownloadRequestPending
+	r.CreatedAt = time.Now().UTC()
+	if err := sess.Collection(downloadRequestsTableName).InsertReturning(r); err != nil {
+		return fmt.Errorf("failed creating download request: %w", err)
+	}
+	return nil
+}
+
+func (r *DownloadRequest) Get(ctx context.Context, sess db.Session) error {
+	if err := sess.Collection(downloadRequestsTableName).Find("id", r.ID).One(r); err != nil {
+		return fmt.Errorf("failed getting download request: %w", err)
+	}
+	return nil
+}
+
+// Decide records an admin's decision if the request is still pending,
+// returning false if someone else decided first.
+func (r *DownloadRequest) Decide(ctx context.Context, sess db.Session, status, decidedBy string) (bool, error) {
+	now := time.Now().UTC()
+	res, err := sess.SQL().ExecContext(ctx, `
+		UPDATE download_requests SET status = ?, decided_by = ?, decided_at = ?
+		WHERE id = ? AND status = ?`,
+		status, decidedBy, now, r.ID, DownloadRequestPending)
+	if err != nil {
+		return false, fmt.Errorf("failed deciding download request: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed deciding download request: %w", err)
+	}
+	if affected == 0 {
+		return false, nil
+	}
+	r.Status = status
+	r.DecidedBy = &decidedBy
+	r.DecidedAt = &now
+	return true, nil
+}
+
+// Reopen puts a decided request back to pending, e.g. when adding an approved torrent failed.
+func (r *DownloadRequest) Reopen(ctx context.Context, sess db.Session) error {
+	if err := sess.Collection(downloadRequestsTableName).Find("id", r.ID).Update(map[string]interface{}{
+		"status":     DownloadRequestPending,
+		"decided_by": nil,
+		"decided_at": nil,
+	}); err != nil {
+		return fmt.Errorf("failed reopening download request: %w", err)
+	}
+	r.Status = DownloadRequestPending
+	r.DecidedBy = nil
+	r.DecidedAt = nil
+	return nil
+}
+
+func (r *DownloadRequest) SetTorrent(ctx context.Context, sess db.Session, torrentID string) error {
+	if err := sess.Collection(downloadRequestsTableName).Find("id", r.ID).Update(map[string]interface{}{
+		"torrent_id": torrentID,
+	}); err != nil {
+		return fmt.Errorf("failed setting download request torrent: %w", err)
+	}
+	r.TorrentID = &torrentID
+	return nil
+}
+
+func (r *DownloadRequest) Delete(ctx context.Context, sess db.Session) error {
+	if err := sess.Collection(downloadRequestsTableName).Find("id", r.ID).Delete(); err != nil {
+		return fmt.Errorf("failed deleting download request: %w", err)
+	}
+	return nil
+}
+
+// GetUserDownloadRequests returns a user's most recent download requests in the guild, newest first.
+func GetUserDownloadRequests(ctx context.Context, sess db.Session, userID, guildID string, limit int) ([]*DownloadRequest, error) {
+	output := make([]*DownloadRequest, 0)
+	cond := InGuild(guildID)
+	cond["requested_by"] = userID
+	if err := sess.Collection(downloadRequestsTableName).Find(cond).OrderBy("-created_at").Limit(limit).All(&output); err != nil {
+		return nil, fmt.Errorf("failed getting download requests: %w", err)
+	}
+	return output, nil
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/upper/db/v4"
+)
+
+const guildSettingsTableName = "guild_settings"
+
+// GuildSettings configures Polly for a single Discord guild.
+type GuildSettings struct {
+	GuildID string `db:"guild_id"`
+	// NotificationChannelID receives a message whenever a torrent added in the guild completes.
+	NotificationChannelID string `db:"notification_channel_id"`
+	// AdminRoles are comma separated role IDs which administer Polly within the guild.
+	AdminRoles string `db:"admin_roles"`
+	// Categories are the comma separated categories torrents in the guild may use, all of them if empty.
+	Categories string    `db:"categories"`
+	UpdatedBy  string    `db:"updated_by"`
+	UpdatedAt  time.Time `db:"updated_at"`
+}
+
+func splitList(list string) []string {
+	if list == "" {
+		return nil
+	}
+	return strings.Split(list, ",")
+}
+
+func (g *GuildSettings) AdminRoleIDs() []string {
+	return splitList(g.AdminRoles)
+}
+
+func (g *GuildSettings) AllowedCategories() []string {
+	return splitList(g.Categories)
+}
+
+// AllowsCategory reports whether torrents in the guild may use the category.
+func (g *GuildSettings) AllowsCategory(category string) bool {
+	allowed := g.AllowedCategories()
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, allowedCategory := range allowed {
+		if allowedCategory == category {
+			return true
+		}
+	}
+	return false
+}
+
+// GetGuildSettings returns the guild's settings, or empty settings if none were saved.
+func GetGuildSettings(ctx context.Context, sess db.Session, guildID string) (*GuildSettings, error) {
+	output := GuildSettings{GuildID: guildID}
+	if guildID == "" {
+		return &output, nil
+	}
+	if err := sess.Collection(guildSettingsTableName).Find("guild_id", guildID).One(&output); err != nil {
+		if errors.Is(err, db.ErrNoMoreRows) {
+			return &output, nil
+		}
+		return nil, fmt.Errorf("failed getting guild settings: %w", err)
+	}
+	return &output, nil
+}
+
+func (g *GuildSettings) Set(ctx context.Context, sess db.Session) error {
+	g.UpdatedAt = time.Now().UTC()
+	if _, err := sess.SQL().ExecContext(ctx, `
+		INSERT INTO guild_settings (guild_id, notification_channel_id, admin_roles, categories, updated_by, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (guild_id) DO UPDATE SET
+			notification_channel_id = EXCLUDED.notification_channel_id,
+			admin_roles = EXCLUDED.admin_roles,
+			categories = EXCLUDED.categories,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = EXCLUDED.updated_at`,
+		g.GuildID, g.NotificationChannelID, g.AdminRoles, g.Categories, g.UpdatedBy, g.UpdatedAt); err != nil {
+		return fmt.Errorf("failed setting guild settings: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package models
+
+const (
+	// StalledLabel is set on torrents which made no progress within the stall window.
+	// Its value is the RFC 3339 time the torrent was flagged.
+	StalledLabel = "stalled"
+	// StalledKeepUntilLabel suppresses stall detection until the RFC 3339 time in its value.
+	StalledKeepUntilLabel = "stalled-keep-until"
+	// SeedingPolicyLabel records why a seeding policy stopped or removed the torrent.
+	SeedingPolicyLabel = "seeding-policy"
+	// DiskGuardLabel records whether the torrent fit on the disk, or why it was paused or rejected.
+	DiskGuardLabel = "disk-guard"
+	// QuotaLabel is set on torrents added without a known size, which are checked against the
+	// requester's quota once transmission knows their size.
+	QuotaLabel = "quota"
+)
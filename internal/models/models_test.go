@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"testing"
 	"time"
 )
@@ -96,3 +97,215 @@ func TestTorrent_String(t *testing.T) {
 		})
 	}
 }
+
+func TestTorrent_Stats(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := map[string]struct {
+		torrent Torrent
+		samples []*TorrentSample
+		want    TorrentStats
+	}{
+		"No samples": {
+			torrent: Torrent{TotalSize: 100},
+			want:    TorrentStats{},
+		},
+		"Single sample": {
+			torrent: Torrent{TotalSize: 100},
+			samples: []*TorrentSample{
+				{SampledAt: start, Downloaded: 10},
+			},
+			want: TorrentStats{},
+		},
+		"Downloading": {
+			torrent: Torrent{TotalSize: 1000, Downloaded: 400, Uploaded: 50},
+			samples: []*TorrentSample{
+				{SampledAt: start, Downloaded: 100, Uploaded: 0},
+				{SampledAt: start.Add(time.Minute), Downloaded: 250, Uploaded: 20},
+				{SampledAt: start.Add(2 * time.Minute), Downloaded: 400, Uploaded: 60},
+			},
+			want: TorrentStats{
+				DownloadRate: 2.5,
+				UploadRate:   0.5,
+				ETA:          4 * time.Minute,
+				Uploaded:     60,
+				Window:       2 * time.Minute,
+			},
+		},
+		"Stalled": {
+			torrent: Torrent{TotalSize: 1000, Downloaded: 400},
+			samples: []*TorrentSample{
+				{SampledAt: start, Downloaded: 400},
+				{SampledAt: start.Add(time.Minute), Downloaded: 400},
+			},
+			want: TorrentStats{
+				Window: time.Minute,
+			},
+		},
+		"Seeding": {
+			torrent: Torrent{TotalSize: 1000, Downloaded: 1000},
+			samples: []*TorrentSample{
+				{SampledAt: start, Downloaded: 900, Uploaded: 100},
+				{SampledAt: start.Add(100 * time.Second), Downloaded: 1000, Uploaded: 300},
+			},
+			want: TorrentStats{
+				DownloadRate: 1,
+				UploadRate:   2,
+				Uploaded:     200,
+				Window:       100 * time.Second,
+			},
+		},
+	}
+	for name, tt := range tests {
+		testData := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if got := testData.torrent.Stats(testData.samples); got != testData.want {
+				t.Errorf("Torrent.Stats() = %+v, want %+v", got, testData.want)
+			}
+		})
+	}
+}
+
+func TestGuildSettings_AllowsCategory(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		categories string
+		category   string
+		want       bool
+	}{
+		"All allowed": {
+			categories: "",
+			category:   "MOVIE",
+			want:       true,
+		},
+		"Allowed": {
+			categories: "MOVIE,TV SHOW",
+			category:   "TV SHOW",
+			want:       true,
+		},
+		"Not allowed": {
+			categories: "MOVIE,TV SHOW",
+			category:   "SOFTWARE",
+			want:       false,
+		},
+	}
+	for name, tt := range tests {
+		testData := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			settings := &GuildSettings{Categories: testData.categories}
+			if got := settings.AllowsCategory(testData.category); got != testData.want {
+				t.Errorf("GuildSettings.AllowsCategory() = %v, want %v", got, testData.want)
+			}
+		})
+	}
+}
+
+func TestTorrentMetadata_Equal(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		a    []string
+		b    []string
+		want bool
+	}{
+		"Same order": {
+			a:    []string{"MOVIE", "MUSIC"},
+			b:    []string{"MOVIE", "MUSIC"},
+			want: true,
+		},
+		"Different order": {
+			a:    []string{"MOVIE", "MUSIC"},
+			b:    []string{"MUSIC", "MOVIE"},
+			want: true,
+		},
+		"Different categories": {
+			a:    []string{"MOVIE", "MUSIC"},
+			b:    []string{"MOVIE", "GAME"},
+			want: false,
+		},
+		"Duplicate categories": {
+			a:    []string{"MOVIE", "MOVIE"},
+			b:    []string{"MOVIE", "MUSIC"},
+			want: false,
+		},
+	}
+	for name, tt := range tests {
+		testData := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			a := &TorrentMetadata{Categories: testData.a}
+			b := &TorrentMetadata{Categories: testData.b}
+			if got := a.Equal(b); got != testData.want {
+				t.Errorf("TorrentMetadata.Equal() = %v, want %v", got, testData.want)
+			}
+		})
+	}
+}
+
+func TestNewAPIKey(t *testing.T) {
+	t.Parallel()
+	key, token, err := NewAPIKey("ci", []string{ScopeRead}, 0, "user")
+	if err != nil {
+		t.Fatalf("NewAPIKey() error = %v", err)
+	}
+	id, secret, ok := ParseAPIKey(token)
+	if !ok {
+		t.Fatalf("ParseAPIKey(%q) failed", token)
+	}
+	if id != key.ID {
+		t.Errorf("ParseAPIKey() id = %q, want %q", id, key.ID)
+	}
+	if !key.Verify(secret) {
+		t.Errorf("APIKey.Verify() rejected the generated secret")
+	}
+	if key.Verify(secret + "0") {
+		t.Errorf("APIKey.Verify() accepted a different secret")
+	}
+	if strings.Contains(key.SecretHash, secret) {
+		t.Errorf("APIKey.SecretHash contains the secret")
+	}
+}
+
+func TestScopesAllow(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		granted  []string
+		required string
+		want     bool
+	}{
+		"None": {
+			required: ScopeRead,
+			want:     false,
+		},
+		"Same": {
+			granted:  []string{ScopeWrite},
+			required: ScopeWrite,
+			want:     true,
+		},
+		"Higher": {
+			granted:  []string{ScopeAdmin},
+			required: ScopeRead,
+			want:     true,
+		},
+		"Lower": {
+			granted:  []string{ScopeRead},
+			required: ScopeWrite,
+			want:     false,
+		},
+		"Unknown": {
+			granted:  []string{"everything"},
+			required: ScopeRead,
+			want:     false,
+		},
+	}
+	for name, tt := range tests {
+		testData := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if got := ScopesAllow(testData.granted, testData.required); got != testData.want {
+				t.Errorf("ScopesAllow() = %v, want %v", got, testData.want)
+			}
+		})
+	}
+}
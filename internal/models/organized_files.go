@@ -0,0 +1,36 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/upper/db/v4"
+)
+
+const organizedFilesTableName = "organized_files"
+
+// OrganizedFile records where a file of a completed torrent was put in a library.
+type OrganizedFile struct {
+	TorrentID       string    `db:"torrent_id"`
+	SourcePath      string    `db:"source_path"`
+	DestinationPath string    `db:"destination_path"`
+	Mode            string    `db:"mode"`
+	OrganizedAt     time.Time `db:"organized_at"`
+}
+
+func (f *OrganizedFile) Create(ctx context.Context, sess db.Session) error {
+	f.OrganizedAt = time.Now().UTC()
+	if _, err := sess.Collection(organizedFilesTableName).Insert(f); err != nil {
+		return fmt.Errorf("failed creating organized file: %w", err)
+	}
+	return nil
+}
+
+func GetOrganizedFiles(ctx context.Context, sess db.Session, torrentID string) ([]*OrganizedFile, error) {
+	output := make([]*OrganizedFile, 0)
+	if err := sess.Collection(organizedFilesTableName).Find("torrent_id", torrentID).OrderBy("destination_path").All(&output); err != nil {
+		return nil, fmt.Errorf("failed getting organized files: %w", err)
+	}
+	return output, nil
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/upper/db/v4"
+)
+
+const userQuotasTableName = "user_quotas"
+
+// UserQuota overrides the configured quota of a single user, zero values are unlimited
+// and blocked users can't add anything.
+type UserQuota struct {
+	UserID      string    `db:"user_id"`
+	MaxActive   int       `db:"max_active"`
+	DailyBytes  uint64    `db:"daily_bytes"`
+	WeeklyBytes uint64    `db:"weekly_bytes"`
+	Blocked     bool      `db:"blocked"`
+	UpdatedBy   string    `db:"updated_by"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+// GetUserQuota returns the user's override, or nil if they don't have one.
+func GetUserQuota(ctx context.Context, sess db.Session, userID string) (*UserQuota, error) {
+	var output UserQuota
+	if err := sess.Collection(userQuotasTableName).Find("user_id", userID).One(&output); err != nil {
+		if errors.Is(err, db.ErrNoMoreRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed getting user quota: %w", err)
+	}
+	return &output, nil
+}
+
+// Set creates or replaces the user's override.
+func (q *UserQuota) Set(ctx context.Context, sess db.Session) error {
+	q.UpdatedAt = time.Now().UTC()
+	if _, err := sess.SQL().ExecContext(ctx, `
+		INSERT INTO user_quotas (user_id, max_active, daily_bytes, weekly_bytes, blocked, updated_by, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (user_id) DO UPDATE SET
+			max_active = EXCLUDED.max_active,
+			daily_bytes = EXCLUDED.daily_bytes,
+			weekly_bytes = EXCLUDED.weekly_bytes,
+			blocked = EXCLUDED.blocked,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = EXCLUDED.updated_at`,
+		q.UserID, q.MaxActive, q.DailyBytes, q.WeeklyBytes, q.Blocked, q.UpdatedBy, q.UpdatedAt); err != nil {
+		return fmt.Errorf("failed setting user quota: %w", err)
+	}
+	return nil
+}
+
+func DeleteUserQuota(ctx context.Context, sess db.Session, userID string) error {
+	if err := sess.Collection(userQuotasTableName).Find("user_id", userID).Delete(); err != nil {
+		return fmt.Errorf("failed deleting user quota: %w", err)
+	}
+	return nil
+}
+
+// UserUsage is how much of their quota a user used.
+type UserUsage struct {
+	Active      int
+	DailyBytes  uint64
+	WeeklyBytes uint64
+}
+
+// GetUserUsage counts the user's unfinished torrents and the size of torrents they added in the last day and week.
+func GetUserUsage(ctx context.Context, sess db.Session, userID string, now time.Time) (UserUsage, error) {
+	var usage UserUsage
+	row, err := sess.SQL().QueryRowContext(ctx, `
+		SELECT
+			COUNT(*) FILTER (WHERE completed_at IS NULL AND deleted_at IS NULL),
+			COALESCE(SUM(total_size) FILTER (WHERE created_at >= ?), 0),
+			COALESCE(SUM(total_size) FILTER (WHERE created_at >= ?), 0)
+		FROM torrents
+		WHERE requested_by = ?`,
+		now.Add(-24*time.Hour).UTC(), now.Add(-7*24*time.Hour).UTC(), userID)
+	if err != nil {
+		return usage, fmt.Errorf("failed querying user usage: %w", err)
+	}
+	if err := row.Scan(&usage.Active, &usage.DailyBytes, &usage.WeeklyBytes); err != nil {
+		return usage, fmt.Errorf("failed scanning user usage: %w", err)
+	}
+	return usage, nil
+}
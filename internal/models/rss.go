@@ -0,0 +1,213 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/upper/db/v4"
+)
+
+const (
+	rssFeedsTableName        = "rss_feeds"
+	rssRulesTableName        = "rss_rules"
+	rssSeenItemsTableName    = "rss_seen_items"
+	rssFailedItemsTableName  = "rss_failed_items"
+	rssRuleEpisodesTableName = "rss_rule_episodes"
+)
+
+type RSSFeed struct {
+	ID        string     `db:"id"`
+	URL       string     `db:"url"`
+	CreatedBy string     `db:"created_by"`
+	CreatedAt time.Time  `db:"created_at"`
+	PolledAt  *time.Time `db:"polled_at"`
+}
+
+// GetOrCreateRSSFeed returns the feed with the URL, creating it if it doesn't exist yet.
+func GetOrCreateRSSFeed(ctx context.Context, sess db.Session, feed *RSSFeed) error {
+	err := transaction(ctx, sess, "GetOrCreateRSSFeed", func(sess db.Session) error {
+		err := sess.Collection(rssFeedsTableName).Find("url", feed.URL).One(feed)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, db.ErrNoMoreRows) {
+			return fmt.Errorf("failed getting rss feed: %w", err)
+		}
+		feed.ID = uuid.NewString()
+		feed.CreatedAt = time.Now().UTC()
+		if err := sess.Collection(rssFeedsTableName).InsertReturning(feed); err != nil {
+			return fmt.Errorf("failed creating rss feed: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed db session: %w", err)
+	}
+	return nil
+}
+
+func GetRSSFeeds(ctx context.Context, sess db.Session) ([]*RSSFeed, error) {
+	output := make([]*RSSFeed, 0)
+	if err := sess.Collection(rssFeedsTableName).Find().OrderBy("created_at").All(&output); err != nil {
+		return nil, fmt.Errorf("failed getting rss feeds: %w", err)
+	}
+	return output, nil
+}
+
+func (f *RSSFeed) Polled(ctx context.Context, sess db.Session) error {
+	now := time.Now().UTC()
+	f.PolledAt = &now
+	if err := sess.Collection(rssFeedsTableName).Find("id", f.ID).Update(f); err != nil {
+		return fmt.Errorf("failed updating rss feed: %w", err)
+	}
+	return nil
+}
+
+// Unseen returns the item GUIDs which haven't been marked as seen yet.
+func (f *RSSFeed) Unseen(ctx context.Context, sess db.Session, guids ...string) ([]string, error) {
+	unseen := make([]string, 0, len(guids))
+	for _, guid := range guids {
+		exists, err := sess.Collection(rssSeenItemsTableName).Find(db.Cond{"feed_id": f.ID, "guid": guid}).Exists()
+		if err != nil {
+			return nil, fmt.Errorf("failed checking seen item: %w", err)
+		}
+		if !exists {
+			unseen = append(unseen, guid)
+		}
+	}
+	return unseen, nil
+}
+
+func (f *RSSFeed) MarkSeen(ctx context.Context, sess db.Session, guid string) error {
+	if _, err := sess.Collection(rssSeenItemsTableName).Insert(map[string]interface{}{
+		"feed_id": f.ID,
+		"guid":    guid,
+		"seen_at": time.Now().UTC(),
+	}); err != nil {
+		return fmt.Errorf("failed creating seen item: %w", err)
+	}
+	if err := sess.Collection(rssFailedItemsTableName).Find(db.Cond{"feed_id": f.ID, "guid": guid}).Delete(); err != nil {
+		return fmt.Errorf("failed deleting failed item: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed counts a failed attempt at adding the item, returning how many attempts have failed so far.
+func (f *RSSFeed) MarkFailed(ctx context.Context, sess db.Session, guid string) (int, error) {
+	row, err := sess.SQL().QueryRowContext(ctx, `
+		INSERT INTO rss_failed_items (feed_id, guid, attempts, failed_at) VALUES (?, ?, 1, ?)
+		ON CONFLICT (feed_id, guid) DO UPDATE SET
+			attempts = rss_failed_items.attempts + 1,
+			failed_at = EXCLUDED.failed_at
+		RETURNING attempts`,
+		f.ID, guid, time.Now().UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed marking item failed: %w", err)
+	}
+	var attempts int
+	if err := row.Scan(&attempts); err != nil {
+		return 0, fmt.Errorf("failed scanning item attempts: %w", err)
+	}
+	return attempts, nil
+}
+
+// TrimSeen deletes all but the most recent seen items of the feed.
+func (f *RSSFeed) TrimSeen(ctx context.Context, sess db.Session, keep int) error {
+	if _, err := sess.SQL().ExecContext(ctx, `
+		DELETE FROM rss_seen_items
+		WHERE feed_id = ? AND guid NOT IN (
+			SELECT guid FROM rss_seen_items WHERE feed_id = ? ORDER BY seen_at DESC LIMIT ?
+		)`, f.ID, f.ID, keep); err != nil {
+		return fmt.Errorf("failed trimming seen items: %w", err)
+	}
+	return nil
+}
+
+type RSSRule struct {
+	ID             string    `db:"id"`
+	FeedID         string    `db:"feed_id"`
+	Pattern        string    `db:"pattern"`
+	Category       string    `db:"category"`
+	MinSize        uint64    `db:"min_size"`
+	MaxSize        uint64    `db:"max_size"`
+	DedupeEpisodes bool      `db:"dedupe_episodes"`
+	CreatedBy      string    `db:"created_by"`
+	ChannelID      string    `db:"channel_id"`
+	GuildID        string    `db:"guild_id"`
+	CreatedAt      time.Time `db:"created_at"`
+}
+
+func (r *RSSRule) Create(ctx context.Context, sess db.Session) error {
+	r.CreatedAt = time.Now().UTC()
+	if err := sess.Collection(rssRulesTableName).InsertReturning(r); err != nil {
+		return fmt.Errorf("failed creating rss rule: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the rule along with its feed once no other rules use it.
+func (r *RSSRule) Delete(ctx context.Context, sess db.Session) error {
+	err := transaction(ctx, sess, "RSSRule.Delete", func(sess db.Session) error {
+		if err := sess.Collection(rssRulesTableName).Find("id", r.ID).One(r); err != nil {
+			return fmt.Errorf("failed getting rss rule: %w", err)
+		}
+		if err := sess.Collection(rssRuleEpisodesTableName).Find("rule_id", r.ID).Delete(); err != nil {
+			return fmt.Errorf("failed deleting rss rule episodes: %w", err)
+		}
+		if err := sess.Collection(rssRulesTableName).Find("id", r.ID).Delete(); err != nil {
+			return fmt.Errorf("failed deleting rss rule: %w", err)
+		}
+		remaining, err := sess.Collection(rssRulesTableName).Find("feed_id", r.FeedID).Count()
+		if err != nil {
+			return fmt.Errorf("failed counting rss rules: %w", err)
+		}
+		if remaining > 0 {
+			return nil
+		}
+		if err := sess.Collection(rssSeenItemsTableName).Find("feed_id", r.FeedID).Delete(); err != nil {
+			return fmt.Errorf("failed deleting seen items: %w", err)
+		}
+		if err := sess.Collection(rssFailedItemsTableName).Find("feed_id", r.FeedID).Delete(); err != nil {
+			return fmt.Errorf("failed deleting failed items: %w", err)
+		}
+		if err := sess.Collection(rssFeedsTableName).Find("id", r.FeedID).Delete(); err != nil {
+			return fmt.Errorf("failed deleting rss feed: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed db session: %w", err)
+	}
+	return nil
+}
+
+func GetRSSRules(ctx context.Context, sess db.Session, args ...interface{}) ([]*RSSRule, error) {
+	output := make([]*RSSRule, 0)
+	if err := sess.Collection(rssRulesTableName).Find(args...).OrderBy("created_at").All(&output); err != nil {
+		return nil, fmt.Errorf("failed getting rss rules: %w", err)
+	}
+	return output, nil
+}
+
+// HasEpisode returns whether the rule already downloaded the episode.
+func (r *RSSRule) HasEpisode(ctx context.Context, sess db.Session, episode string) (bool, error) {
+	exists, err := sess.Collection(rssRuleEpisodesTableName).Find(db.Cond{"rule_id": r.ID, "episode": episode}).Exists()
+	if err != nil {
+		return false, fmt.Errorf("failed checking rss rule episode: %w", err)
+	}
+	return exists, nil
+}
+
+func (r *RSSRule) AddEpisode(ctx context.Context, sess db.Session, episode, torrentID string) error {
+	if _, err := sess.Collection(rssRuleEpisodesTableName).Insert(map[string]interface{}{
+		"rule_id":    r.ID,
+		"episode":    episode,
+		"torrent_id": torrentID,
+	}); err != nil {
+		return fmt.Errorf("failed creating rss rule episode: %w", err)
+	}
+	return nil
+}
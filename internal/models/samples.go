@@ -0,0 +1,117 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/upper/db/v4"
+)
+
+const torrentSamplesTableName = "torrent_samples"
+
+type TorrentSample struct {
+	TorrentID  string    `db:"torrent_id"`
+	SampledAt  time.Time `db:"sampled_at"`
+	Downloaded uint64    `db:"downloaded"`
+	Uploaded   uint64    `db:"uploaded"`
+}
+
+func (t *Torrent) Sample(now time.Time) *TorrentSample {
+	return &TorrentSample{
+		TorrentID:  t.ID,
+		SampledAt:  now.UTC(),
+		Downloaded: t.Downloaded,
+		Uploaded:   t.Uploaded,
+	}
+}
+
+func (t *TorrentSample) Create(ctx context.Context, sess db.Session) error {
+	if _, err := sess.Collection(torrentSamplesTableName).Insert(t); err != nil {
+		return fmt.Errorf("failed creating torrent sample: %w", err)
+	}
+	return nil
+}
+
+// GetTorrentSamples returns the samples of a torrent taken since the given time, oldest first.
+func GetTorrentSamples(ctx context.Context, sess db.Session, torrentID string, since time.Time) ([]*TorrentSample, error) {
+	output := make([]*TorrentSample, 0)
+	if err := sess.Collection(torrentSamplesTableName).Find(db.Cond{
+		"torrent_id":    torrentID,
+		"sampled_at >=": since.UTC(),
+	}).OrderBy("sampled_at").All(&output); err != nil {
+		return nil, fmt.Errorf("failed getting torrent samples: %w", err)
+	}
+	return output, nil
+}
+
+// PruneTorrentSamples deletes samples older than the retention and thins out samples older than
+// downsampleAfter so that only the first sample in every resolution sized bucket is kept.
+func PruneTorrentSamples(ctx context.Context, sess db.Session, retention, downsampleAfter, resolution time.Duration) error {
+	now := time.Now().UTC()
+	err := transaction(ctx, sess, "PruneTorrentSamples", func(sess db.Session) error {
+		if err := sess.Collection(torrentSamplesTableName).Find("sampled_at <", now.Add(-retention)).Delete(); err != nil {
+			return fmt.Errorf("failed deleting expired samples: %w", err)
+		}
+		if _, err := sess.SQL().ExecContext(ctx, `
+			DELETE FROM torrent_samples
+			WHERE sampled_at < ? AND (torrent_id, sampled_at) NOT IN (
+				SELECT torrent_id, MIN(sampled_at)
+				FROM torrent_samples
+				WHERE sampled_at < ?
+				GROUP BY torrent_id, FLOOR(EXTRACT(EPOCH FROM sampled_at) / ?)
+			)`, now.Add(-downsampleAfter), now.Add(-downsampleAfter), resolution.Seconds()); err != nil {
+			return fmt.Errorf("failed downsampling samples: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed db session: %w", err)
+	}
+	return nil
+}
+
+type TorrentStats struct {
+	// DownloadRate and UploadRate are average rates in bytes per second.
+	DownloadRate float64
+	UploadRate   float64
+	// ETA is the estimated time left until the download completes, zero if unknown.
+	ETA time.Duration
+	// Uploaded is the number of bytes uploaded within the sampled window.
+	Uploaded uint64
+	// Window is the time between the first and last sample used.
+	Window time.Duration
+}
+
+// Stats calculates rates and an ETA for the torrent from its samples, which must be ordered oldest first.
+func (t *Torrent) Stats(samples []*TorrentSample) TorrentStats {
+	var stats TorrentStats
+	if len(samples) < 2 {
+		return stats
+	}
+	first, last := samples[0], samples[len(samples)-1]
+	stats.Window = last.SampledAt.Sub(first.SampledAt)
+	if stats.Window <= 0 {
+		return stats
+	}
+	if last.Downloaded > first.Downloaded {
+		stats.DownloadRate = float64(last.Downloaded-first.Downloaded) / stats.Window.Seconds()
+	}
+	if last.Uploaded > first.Uploaded {
+		stats.Uploaded = last.Uploaded - first.Uploaded
+		stats.UploadRate = float64(stats.Uploaded) / stats.Window.Seconds()
+	}
+	if t.Downloaded < t.TotalSize && stats.DownloadRate > 0 {
+		remaining := float64(t.TotalSize - t.Downloaded)
+		stats.ETA = time.Duration(remaining / stats.DownloadRate * float64(time.Second)).Round(time.Second)
+	}
+	return stats
+}
+
+func (t *Torrent) GetStats(ctx context.Context, sess db.Session, window time.Duration) (TorrentStats, error) {
+	samples, err := GetTorrentSamples(ctx, sess, t.ID, time.Now().Add(-window))
+	if err != nil {
+		return TorrentStats{}, err
+	}
+	return t.Stats(samples), nil
+}
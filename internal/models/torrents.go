@@ -41,6 +41,8 @@ const torrentTableName = "torrents"
 
 type TorrentMetadata struct {
 	FriendlyName string `db:"friendly_name"`
+	RequestedBy  string `db:"requested_by"`
+	GuildID      string `db:"guild_id"`
 	Categories   []string
 	Labels       map[string]string
 	UpdatedAt    *time.Time `db:"updated_at"`
@@ -60,6 +62,34 @@ func (t *TorrentMetadata) Equal(s *TorrentMetadata) bool {
 	if t.FriendlyName != s.FriendlyName {
 		return false
 	}
+	if t.RequestedBy != s.RequestedBy {
+		return false
+	}
+	if t.GuildID != s.GuildID {
+		return false
+	}
+	if len(t.Labels) != len(s.Labels) {
+		return false
+	}
+	for k, v := range t.Labels {
+		if w, ok := s.Labels[k]; !ok || v != w {
+			return false
+		}
+	}
+	if len(t.Categories) != len(s.Categories) {
+		return false
+	}
+	// Categories are unordered, so compare how often each appears
+	categories := make(map[string]int, len(t.Categories))
+	for _, category := range t.Categories {
+		categories[category]++
+	}
+	for _, category := range s.Categories {
+		if categories[category] == 0 {
+			return false
+		}
+		categories[category]--
+	}
 	if t.UpdatedAt == nil && s.UpdatedAt != nil ||
 		s.UpdatedAt == nil && t.UpdatedAt != nil {
 		return false
@@ -91,6 +121,15 @@ type Torrent struct {
 	Uploaded    uint64     `db:"uploaded"`
 }
 
+// Ratio is the uploaded bytes per byte of the torrent's size, so data verified from disk
+// rather than downloaded still counts towards it.
+func (t *Torrent) Ratio() float64 {
+	if t.TotalSize == 0 {
+		return 0
+	}
+	return float64(t.Uploaded) / float64(t.TotalSize)
+}
+
 func (t *Torrent) String() string {
 	var completedString string
 	var percentCompleted float32
@@ -238,8 +277,96 @@ func (t torrentLabels) Diff(id string, existingLabels torrentLabels) (additions,
 	return
 }
 
+// SetLabel creates or replaces a single label of the torrent without touching the rest of its metadata.
+func (t *Torrent) SetLabel(ctx context.Context, sess db.Session, key, value string) error {
+	if _, err := sess.SQL().ExecContext(ctx, `
+		INSERT INTO torrent_labels (torrent_id, key, value) VALUES (?, ?, ?)
+		ON CONFLICT (torrent_id, key) DO UPDATE SET value = EXCLUDED.value`,
+		t.ID, key, value); err != nil {
+		return fmt.Errorf("failed setting label %q: %w", key, err)
+	}
+	if t.TorrentMetadata == nil {
+		t.TorrentMetadata = &TorrentMetadata{}
+	}
+	if t.Labels == nil {
+		t.Labels = make(map[string]string)
+	}
+	t.Labels[key] = value
+	return nil
+}
+
+func (t *Torrent) DeleteLabel(ctx context.Context, sess db.Session, key string) error {
+	if err := sess.Collection(torrentLabelsTableName).Find(db.Cond{"torrent_id": t.ID, "key": key}).Delete(); err != nil {
+		return fmt.Errorf("failed deleting label %q: %w", key, err)
+	}
+	if t.TorrentMetadata != nil {
+		delete(t.Labels, key)
+	}
+	return nil
+}
+
+func (t *Torrent) Label(key string) (string, bool) {
+	if t.TorrentMetadata == nil {
+		return "", false
+	}
+	value, ok := t.Labels[key]
+	return value, ok
+}
+
+// MarkDeleted records that the torrent was removed from transmission.
+func (t *Torrent) MarkDeleted(ctx context.Context, sess db.Session) error {
+	now := time.Now().UTC()
+	if err := sess.Collection(torrentTableName).Find("id", t.ID).Update(map[string]interface{}{
+		"deleted_at": now,
+		"updated_at": now,
+	}); err != nil {
+		return fmt.Errorf("failed marking torrent deleted: %w", err)
+	}
+	if t.TorrentMetadata == nil {
+		t.TorrentMetadata = &TorrentMetadata{}
+	}
+	t.DeletedAt = &now
+	t.UpdatedAt = &now
+	return nil
+}
+
+// MarkNotified records that every completed torrent subscriber received the torrent.
+func (t *Torrent) MarkNotified(ctx context.Context, sess db.Session) error {
+	if err := sess.Collection(torrentTableName).Find("id", t.ID).Update(map[string]interface{}{
+		"notified_at": time.Now().UTC(),
+	}); err != nil {
+		return fmt.Errorf("failed marking torrent notified: %w", err)
+	}
+	return nil
+}
+
+// GetUnnotifiedTorrents returns the completed torrents which not every subscriber received,
+// e.g. because Polly stopped before they could.
+func GetUnnotifiedTorrents(ctx context.Context, sess db.Session) ([]*Torrent, error) {
+	return GetTorrents(ctx, sess, 0, db.Cond{
+		"completed_at": db.IsNotNull(),
+		"notified_at":  db.IsNull(),
+		"deleted_at":   db.IsNull(),
+	})
+}
+
 const torrentCategoriesTableName = "torrent_categories"
 
+// AddCategory adds a single category to the torrent without touching the rest of its metadata.
+func (t *Torrent) AddCategory(ctx context.Context, sess db.Session, category string) error {
+	if _, err := sess.Collection(torrentCategoriesTableName).Insert(torrentCategory{
+		TorrentID: t.ID,
+		Category:  category,
+	}); err != nil {
+		return fmt.Errorf("failed adding category %q: %w", category, err)
+	}
+	if t.TorrentMetadata == nil {
+		t.TorrentMetadata = &TorrentMetadata{}
+	}
+	t.Categories = append(t.Categories, category)
+	return nil
+}
+
 type torrentCategory struct {
 	TorrentID string `db:"torrent_id"`
 	Category  string `db:"category"`
@@ -281,7 +408,7 @@ func (t torrentCategories) Diff(id string, existingCategories torrentCategories)
 func (t *Torrent) Set(ctx context.Context, sess db.Session) (bool, error) {
 	t.setRawValues()
 	var completed bool
-	err := sess.TxContext(ctx, func(sess db.Session) error {
+	err := transaction(ctx, sess, "Torrent.Set", func(sess db.Session) error {
 		// Get current torrent record
 		var existing Torrent
 		existingRecord := sess.Collection(torrentTableName).Find("id", t.ID)
@@ -293,8 +420,20 @@ func (t *Torrent) Set(ctx context.Context, sess db.Session) (bool, error) {
 			if err := existingRecord.One(&existing); err != nil {
 				return fmt.Errorf("failed inserting record: %w", err)
 			}
+			if existing.TorrentMetadata == nil {
+				existing.TorrentMetadata = &TorrentMetadata{}
+			}
+			if err := sess.Collection(torrentLabelsTableName).Find("torrent_id", t.ID).All(&existing.rawLabels); err != nil {
+				return fmt.Errorf("failed getting existing labels: %w", err)
+			}
+			if err := sess.Collection(torrentCategoriesTableName).Find("torrent_id", t.ID).All(&existing.rawCategories); err != nil {
+				return fmt.Errorf("failed getting existing categories: %w", err)
+			}
+			existing.getRawValues()
 			if t.TorrentMetadata == nil {
+				// Keep the existing metadata, including labels and categories
 				t.TorrentMetadata = existing.TorrentMetadata
+				t.setRawValues()
 			}
 			if existing.CompletedAt == nil && t.CompletedAt != nil {
 				completed = true
@@ -336,7 +475,7 @@ func (t *Torrent) Set(ctx context.Context, sess db.Session) (bool, error) {
 			}
 		}
 		return nil
-	}, nil)
+	})
 	if err != nil {
 		return false, fmt.Errorf("failed db session: %w", err)
 	}
@@ -347,29 +486,101 @@ func (t *Torrent) Get(ctx context.Context, sess db.Session) error {
 	if err := sess.Collection(torrentTableName).Find("id", t.ID).One(t); err != nil {
 		return fmt.Errorf("failed getting record: %w", err)
 	}
-	if err := sess.Collection(torrentLabelsTableName).Find("torrent_id", t.ID).All(t.rawLabels); err != nil {
+	if err := sess.Collection(torrentLabelsTableName).Find("torrent_id", t.ID).All(&t.rawLabels); err != nil {
 		return fmt.Errorf("failed getting labels: %w", err)
 	}
-	if err := sess.Collection(torrentCategoriesTableName).Find("torrent_id", t.ID).All(t.rawCategories); err != nil {
+	if err := sess.Collection(torrentCategoriesTableName).Find("torrent_id", t.ID).All(&t.rawCategories); err != nil {
 		return fmt.Errorf("failed getting categories: %w", err)
 	}
 	t.getRawValues()
 	return nil
 }
 
-func GetTorrents(ctx context.Context, sess db.Session, args ...interface{}) ([]*Torrent, error) {
+// GetTorrentsWithLabel returns the torrents which have the label set, regardless of its value.
+func GetTorrentsWithLabel(ctx context.Context, sess db.Session, key string) ([]*Torrent, error) {
+	labels := make(torrentLabels, 0)
+	if err := sess.Collection(torrentLabelsTableName).Find("key", key).All(&labels); err != nil {
+		return nil, fmt.Errorf("failed getting labels: %w", err)
+	}
+	if len(labels) == 0 {
+		return []*Torrent{}, nil
+	}
+	ids := make([]string, 0, len(labels))
+	for _, label := range labels {
+		ids = append(ids, label.TorrentID)
+	}
+	return GetTorrents(ctx, sess, 0, db.Cond{"id IN": ids})
+}
+
+// GetTorrents returns the newest torrents matching the conditions, at most limit of them unless limit is zero.
+func GetTorrents(ctx context.Context, sess db.Session, limit int, args ...interface{}) ([]*Torrent, error) {
 	output := make([]*Torrent, 0)
-	if err := sess.Collection(torrentTableName).Find(args...).OrderBy("created_at").Limit(10).All(&output); err != nil {
+	result := sess.Collection(torrentTableName).Find(args...).OrderBy("-created_at")
+	if limit > 0 {
+		result = result.Limit(limit)
+	}
+	if err := result.All(&output); err != nil {
 		return nil, fmt.Errorf("failed getting records: %w", err)
 	}
-	for _, torrent := range output {
-		if err := sess.Collection(torrentLabelsTableName).Find("torrent_id", torrent.ID).All(&torrent.rawLabels); err != nil {
-			return nil, fmt.Errorf("failed getting labels: %w", err)
-		}
-		if err := sess.Collection(torrentCategoriesTableName).Find("torrent_id", torrent.ID).All(&torrent.rawCategories); err != nil {
-			return nil, fmt.Errorf("failed getting categories: %w", err)
-		}
-		torrent.getRawValues()
+	if err := getTorrentsMetadata(ctx, sess, output); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// GetRequestedTorrents returns the most recent torrents a user requested in the guild, newest first.
+func GetRequestedTorrents(ctx context.Context, sess db.Session, userID, guildID string, limit int) ([]*Torrent, error) {
+	output := make([]*Torrent, 0)
+	cond := InGuild(guildID)
+	cond["requested_by"] = userID
+	if err := sess.Collection(torrentTableName).Find(cond).OrderBy("-created_at").Limit(limit).All(&output); err != nil {
+		return nil, fmt.Errorf("failed getting records: %w", err)
+	}
+	if err := getTorrentsMetadata(ctx, sess, output); err != nil {
+		return nil, err
 	}
 	return output, nil
 }
+
+// getTorrentsMetadata loads the labels and categories of all the torrents with one query each.
+func getTorrentsMetadata(ctx context.Context, sess db.Session, torrents []*Torrent) error {
+	if len(torrents) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(torrents))
+	for _, torrent := range torrents {
+		ids = append(ids, torrent.ID)
+	}
+	labels := make(torrentLabels, 0)
+	if err := sess.Collection(torrentLabelsTableName).Find(db.Cond{"torrent_id IN": ids}).All(&labels); err != nil {
+		return fmt.Errorf("failed getting labels: %w", err)
+	}
+	categories := make(torrentCategories, 0)
+	if err := sess.Collection(torrentCategoriesTableName).Find(db.Cond{"torrent_id IN": ids}).All(&categories); err != nil {
+		return fmt.Errorf("failed getting categories: %w", err)
+	}
+	labelsByTorrent := make(map[string]torrentLabels, len(torrents))
+	for _, label := range labels {
+		labelsByTorrent[label.TorrentID] = append(labelsByTorrent[label.TorrentID], label)
+	}
+	categoriesByTorrent := make(map[string]torrentCategories, len(torrents))
+	for _, category := range categories {
+		categoriesByTorrent[category.TorrentID] = append(categoriesByTorrent[category.TorrentID], category)
+	}
+	for _, torrent := range torrents {
+		torrent.rawLabels = labelsByTorrent[torrent.ID]
+		torrent.rawCategories = categoriesByTorrent[torrent.ID]
+		torrent.getRawValues()
+	}
+	return nil
+}
+
+// VisibleIn reports whether the torrent was added in the guild, or outside of any guild.
+func (t *Torrent) VisibleIn(guildID string) bool {
+	return t.TorrentMetadata == nil || t.GuildID == "" || t.GuildID == guildID
+}
+
+// InGuild matches torrents added in the guild, and those added outside of any guild.
+func InGuild(guildID string) db.Cond {
+	return db.Cond{"guild_id IN": []string{guildID, ""}}
+}
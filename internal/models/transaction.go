@@ -0,0 +1,25 @@
+package models
+
+import (
+	"context"
+
+	"github.com/bobcob7/polly-bot/internal/tracing"
+	"github.com/upper/db/v4"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = tracing.Tracer("internal/models")
+
+// transaction runs fn in a database transaction, traced as name.
+func transaction(ctx context.Context, sess db.Session, name string, fn func(sess db.Session) error) (err error) {
+	ctx, span := tracer.Start(ctx, "db.transaction "+name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.name", sess.Name()),
+			attribute.String("db.operation", name),
+		),
+	)
+	defer func() { tracing.End(span, err) }()
+	return sess.TxContext(ctx, fn, nil)
+}
@@ -0,0 +1,37 @@
+package organizer
+
+import (
+	"errors"
+	"fmt"
+)
+
+type invalidLayoutError struct {
+	path string
+}
+
+func (i invalidLayoutError) Error() string {
+	return fmt.Sprintf("layout rendered an invalid path: %q", i.path)
+}
+
+var errRarUnsupported = errors.New("no command configured to extract rar archives")
+
+type unsafeArchivePathError struct {
+	path string
+}
+
+func (u unsafeArchivePathError) Error() string {
+	return fmt.Sprintf("archive contains a path outside the destination: %q", u.path)
+}
+
+type commandError struct {
+	err    error
+	output string
+}
+
+func (c commandError) Error() string {
+	return fmt.Sprintf("command failed: %v: %s", c.err, c.output)
+}
+
+func (c commandError) Unwrap() error {
+	return c.err
+}
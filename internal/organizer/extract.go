@@ -0,0 +1,214 @@
+package organizer
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/bobcob7/polly-bot/internal/models"
+	"go.uber.org/zap"
+)
+
+const (
+	zipArchive = "zip"
+	rarArchive = "rar"
+)
+
+var (
+	rarPartRegexp   = regexp.MustCompile(`(?i)^(.*)\.part(\d+)\.rar$`)
+	rarVolumeRegexp = regexp.MustCompile(`(?i)^(.*)\.(rar|r\d{2,3})$`)
+	zipRegexp       = regexp.MustCompile(`(?i)\.zip$`)
+)
+
+// Extraction is the result of extracting one archive of a completed torrent.
+type Extraction struct {
+	Torrent     *models.Torrent
+	Archive     string
+	Destination string
+	Err         error
+}
+
+// archiveSet is an archive split over one or more files, extraction starts from the first volume.
+type archiveSet struct {
+	kind     string
+	first    plannedFile
+	hasFirst bool
+	parts    []plannedFile
+}
+
+// findArchives groups the files into archive sets, returning the files which aren't part of one.
+// Volumes of a rar archive without a first volume are treated as regular files.
+func findArchives(files []plannedFile) ([]archiveSet, []plannedFile) {
+	sets := make(map[string]*archiveSet)
+	keys := make([]string, 0)
+	rest := make([]plannedFile, 0, len(files))
+	add := func(key, kind string, file plannedFile, first bool) {
+		set, ok := sets[key]
+		if !ok {
+			set = &archiveSet{kind: kind}
+			sets[key] = set
+			keys = append(keys, key)
+		}
+		set.parts = append(set.parts, file)
+		if first {
+			set.first, set.hasFirst = file, true
+		}
+	}
+	for _, file := range files {
+		dir, name := filepath.Split(file.source)
+		if match := rarPartRegexp.FindStringSubmatch(name); match != nil {
+			part, _ := strconv.Atoi(match[2])
+			add(dir+match[1]+".rar", rarArchive, file, part == 1)
+		} else if match := rarVolumeRegexp.FindStringSubmatch(name); match != nil {
+			add(dir+match[1]+".rar", rarArchive, file, strings.EqualFold(match[2], "rar"))
+		} else if zipRegexp.MatchString(name) {
+			add(file.source, zipArchive, file, true)
+		} else {
+			rest = append(rest, file)
+		}
+	}
+	archives := make([]archiveSet, 0, len(keys))
+	for _, key := range keys {
+		set := sets[key]
+		if !set.hasFirst {
+			rest = append(rest, set.parts...)
+			continue
+		}
+		archives = append(archives, *set)
+	}
+	return archives, rest
+}
+
+// Extract extracts every archive of the torrent into its library directory, or next to the archive
+// if it has no library. Existing files are never overwritten so seeding files are left intact.
+func (o *Organizer) Extract(ctx context.Context, torrent *models.Torrent) ([]*Extraction, error) {
+	if !o.config.Extract.Enabled {
+		return nil, nil
+	}
+	source, directory, err := o.locate(torrent)
+	if err != nil {
+		return nil, err
+	}
+	if directory == "" {
+		directory = source
+		if info, err := os.Stat(source); err == nil && !info.IsDir() {
+			directory = filepath.Dir(source)
+		}
+	}
+	files, err := plan(source, directory)
+	if err != nil {
+		return nil, err
+	}
+	archives, _ := findArchives(files)
+	extractions := make([]*Extraction, 0, len(archives))
+	for _, archive := range archives {
+		extraction := &Extraction{
+			Torrent:     torrent,
+			Archive:     filepath.Base(archive.first.source),
+			Destination: filepath.Dir(archive.first.destination),
+		}
+		logger := o.logger.With(
+			zap.String("archive", archive.first.source),
+			zap.String("destination", extraction.Destination),
+		)
+		if o.config.DryRun {
+			logger.Info("would extract archive")
+			continue
+		}
+		switch archive.kind {
+		case zipArchive:
+			extraction.Err = extractZip(archive.first.source, extraction.Destination)
+		case rarArchive:
+			extraction.Err = o.extractRar(ctx, archive.first.source, extraction.Destination)
+		}
+		if extraction.Err != nil {
+			logger.Error("failed to extract archive", zap.Error(extraction.Err))
+		} else {
+			logger.Info("extracted archive")
+		}
+		extractions = append(extractions, extraction)
+	}
+	return extractions, nil
+}
+
+func extractZip(archive, destination string) error {
+	reader, err := zip.OpenReader(archive)
+	if err != nil {
+		return fmt.Errorf("failed opening zip archive: %w", err)
+	}
+	defer reader.Close()
+	for _, file := range reader.File {
+		path := filepath.Join(destination, filepath.FromSlash(file.Name))
+		if path != destination && !strings.HasPrefix(path, destination+string(filepath.Separator)) {
+			return unsafeArchivePathError{file.Name}
+		}
+		if file.FileInfo().IsDir() {
+			if err := os.MkdirAll(path, 0o755); err != nil {
+				return fmt.Errorf("failed creating directory: %w", err)
+			}
+			continue
+		}
+		if err := extractZipFile(file, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func extractZipFile(file *zip.File, path string) (err error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed creating directory: %w", err)
+	}
+	out, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if errors.Is(err, fs.ErrExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed creating extracted file: %w", err)
+	}
+	defer func() {
+		if closeErr := out.Close(); closeErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed closing extracted file: %w", closeErr))
+		}
+	}()
+	in, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("failed opening archived file: %w", err)
+	}
+	defer in.Close()
+	//nolint: gosec
+	if _, err := io.Copy(out, in); err != nil {
+		return fmt.Errorf("failed extracting file: %w", err)
+	}
+	return nil
+}
+
+// extractRar runs the configured command with the archive and destination substituted into its arguments.
+func (o *Organizer) extractRar(ctx context.Context, archive, destination string) error {
+	args := strings.Fields(o.config.Extract.RarCommand)
+	if len(args) == 0 {
+		return errRarUnsupported
+	}
+	replacer := strings.NewReplacer("{archive}", archive, "{destination}", destination)
+	for i := range args {
+		args[i] = replacer.Replace(args[i])
+	}
+	if err := os.MkdirAll(destination, 0o755); err != nil {
+		return fmt.Errorf("failed creating destination directory: %w", err)
+	}
+	//nolint: gosec
+	output, err := exec.CommandContext(ctx, args[0], args[1:]...).CombinedOutput()
+	if err != nil {
+		return commandError{err: err, output: strings.TrimSpace(string(output))}
+	}
+	return nil
+}
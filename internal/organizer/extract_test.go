@@ -0,0 +1,99 @@
+package organizer
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFindArchives(t *testing.T) {
+	t.Parallel()
+	files := func(names ...string) []plannedFile {
+		output := make([]plannedFile, 0, len(names))
+		for _, name := range names {
+			output = append(output, plannedFile{source: "/src/" + name, destination: "/dst/" + name})
+		}
+		return output
+	}
+	tests := map[string]struct {
+		files     []plannedFile
+		wantFirst []string
+		wantRest  []plannedFile
+	}{
+		"Numbered parts": {
+			files:     files("a.part2.rar", "a.part01.rar", "a.nfo"),
+			wantFirst: []string{"/src/a.part01.rar"},
+			wantRest:  files("a.nfo"),
+		},
+		"Old style volumes": {
+			files:     files("a.r00", "a.rar", "a.r01", "b.zip"),
+			wantFirst: []string{"/src/a.rar", "/src/b.zip"},
+			wantRest:  files(),
+		},
+		"Missing first volume": {
+			files:     files("a.r00", "a.r01", "a.mkv"),
+			wantFirst: []string{},
+			wantRest:  files("a.mkv", "a.r00", "a.r01"),
+		},
+	}
+	for name, tt := range tests {
+		testData := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			archives, rest := findArchives(testData.files)
+			first := make([]string, 0, len(archives))
+			for _, archive := range archives {
+				first = append(first, archive.first.source)
+			}
+			if !reflect.DeepEqual(first, testData.wantFirst) {
+				t.Errorf("findArchives() archives = %v, want %v", first, testData.wantFirst)
+			}
+			if !reflect.DeepEqual(rest, testData.wantRest) {
+				t.Errorf("findArchives() rest = %v, want %v", rest, testData.wantRest)
+			}
+		})
+	}
+}
+
+func TestExtractZip(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "a.zip")
+	f, err := os.Create(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := zip.NewWriter(f)
+	for name, content := range map[string]string{"sub/a.txt": "new", "seeding.txt": "new"} {
+		fw, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "seeding.txt"), []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := extractZip(archive, dir); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{"sub/a.txt": "new", "seeding.txt": "old"} {
+		got, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("extracted %s = %q, want %q", name, got, want)
+		}
+	}
+}
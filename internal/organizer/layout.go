@@ -0,0 +1,117 @@
+package organizer
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/bobcob7/polly-bot/internal/config"
+	"github.com/bobcob7/polly-bot/internal/models"
+	"github.com/bobcob7/polly-bot/internal/torrent"
+)
+
+// LayoutData is available to library layout templates.
+type LayoutData struct {
+	// Name is the friendly name of the torrent, falling back to its name in transmission.
+	Name     string
+	Category string
+	Labels   map[string]string
+	// Release is parsed from the torrent's name, e.g. {{.Release.Title}} ({{.Release.Year}}).
+	Release torrent.Release
+}
+
+func newLayoutData(t *models.Torrent) LayoutData {
+	data := LayoutData{
+		Name:    t.NameString(),
+		Labels:  map[string]string{},
+		Release: torrent.ParseRelease(t.Name),
+	}
+	if t.TorrentMetadata != nil {
+		if len(t.Categories) != 0 {
+			data.Category = t.Categories[0]
+		}
+		for k, v := range t.Labels {
+			data.Labels[k] = v
+		}
+	}
+	return data
+}
+
+// library returns the library for the first of the torrent's categories with one,
+// falling back to the library without a category.
+func library(libraries []config.Library, category string) (config.Library, bool) {
+	for _, library := range libraries {
+		if library.Category != "" && strings.EqualFold(library.Category, category) {
+			return library, true
+		}
+	}
+	for _, library := range libraries {
+		if library.Category == "" {
+			return library, true
+		}
+	}
+	return config.Library{}, false
+}
+
+// destination renders the library layout into a directory within the library.
+func destination(library config.Library, data LayoutData) (string, error) {
+	tmpl, err := template.New("layout").Option("missingkey=zero").Parse(library.Layout)
+	if err != nil {
+		return "", fmt.Errorf("failed parsing layout: %w", err)
+	}
+	var rendered strings.Builder
+	if err := tmpl.Execute(&rendered, data); err != nil {
+		return "", fmt.Errorf("failed rendering layout: %w", err)
+	}
+	rel := filepath.Clean(filepath.FromSlash(strings.TrimSpace(rendered.String())))
+	if rel == "." || filepath.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", invalidLayoutError{rendered.String()}
+	}
+	return filepath.Join(library.Directory, rel), nil
+}
+
+// plannedFile is a file to be put at a destination.
+type plannedFile struct {
+	source      string
+	destination string
+}
+
+// plan lists every file of the torrent's content with its destination in the directory.
+// A single file torrent is put directly into the directory.
+func plan(source, directory string) ([]plannedFile, error) {
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting torrent content: %w", err)
+	}
+	if !info.IsDir() {
+		return []plannedFile{{
+			source:      source,
+			destination: filepath.Join(directory, filepath.Base(source)),
+		}}, nil
+	}
+	files := make([]plannedFile, 0)
+	err = filepath.WalkDir(source, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, plannedFile{
+			source:      path,
+			destination: filepath.Join(directory, rel),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed walking torrent content: %w", err)
+	}
+	return files, nil
+}
@@ -0,0 +1,86 @@
+package organizer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/bobcob7/polly-bot/internal/config"
+)
+
+func TestDestination(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		layout  string
+		data    LayoutData
+		want    string
+		wantErr bool
+	}{
+		"Name": {
+			layout: "Movies/{{.Name}}",
+			data:   LayoutData{Name: "Film (2001)"},
+			want:   "/library/Movies/Film (2001)",
+		},
+		"Label": {
+			layout: `TV/{{.Name}}/Season {{index .Labels "season"}}`,
+			data:   LayoutData{Name: "Show", Labels: map[string]string{"season": "01"}},
+			want:   "/library/TV/Show/Season 01",
+		},
+		"Escape": {
+			layout:  "../{{.Name}}",
+			data:    LayoutData{Name: "x"},
+			wantErr: true,
+		},
+		"Empty": {
+			layout:  "{{.Category}}",
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		testData := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := destination(config.Library{Directory: "/library", Layout: testData.layout}, testData.data)
+			if (err != nil) != testData.wantErr {
+				t.Errorf("destination() error = %v, wantErr %v", err, testData.wantErr)
+				return
+			}
+			if got != filepath.FromSlash(testData.want) && !testData.wantErr {
+				t.Errorf("destination() = %v, want %v", got, testData.want)
+			}
+		})
+	}
+}
+
+func TestPlan(t *testing.T) {
+	t.Parallel()
+	source := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(source, "Show", "Extras"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"Show/a.mkv", "Show/Extras/b.mkv", "c.mkv"} {
+		if err := os.WriteFile(filepath.Join(source, filepath.FromSlash(name)), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := plan(filepath.Join(source, "Show"), "/library")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []plannedFile{
+		{source: filepath.Join(source, "Show", "Extras", "b.mkv"), destination: filepath.Join("/library", "Extras", "b.mkv")},
+		{source: filepath.Join(source, "Show", "a.mkv"), destination: filepath.Join("/library", "a.mkv")},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("plan() = %v, want %v", got, want)
+	}
+	got, err = plan(filepath.Join(source, "c.mkv"), "/library")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []plannedFile{{source: filepath.Join(source, "c.mkv"), destination: filepath.Join("/library", "c.mkv")}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("plan() = %v, want %v", got, want)
+	}
+}
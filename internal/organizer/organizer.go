@@ -0,0 +1,179 @@
+package organizer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bobcob7/polly-bot/internal/config"
+	"github.com/bobcob7/polly-bot/internal/models"
+	"github.com/upper/db/v4"
+	"go.uber.org/zap"
+)
+
+// Organizer puts the content of completed torrents into the library of their category
+// and extracts the archives they contain.
+type Organizer struct {
+	CompletedTorrents chan *models.Torrent
+	logger            *zap.Logger
+	config            config.Organizer
+	downloadDirectory string
+	sess              db.Session
+	extractions       chan<- *Extraction
+}
+
+func NewOrganizer(cfg config.Organizer, downloadDirectory string, sess db.Session) *Organizer {
+	return &Organizer{
+		CompletedTorrents: make(chan *models.Torrent, 16),
+		logger:            zap.L().With(zap.String("component", "organizer")),
+		config:            cfg,
+		downloadDirectory: downloadDirectory,
+		sess:              sess,
+	}
+}
+
+// SubscribeExtractions sends the result of every archive extraction.
+func (o *Organizer) SubscribeExtractions(c chan<- *Extraction) {
+	o.extractions = c
+}
+
+func (o *Organizer) Run(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case torrent := <-o.CompletedTorrents:
+			logger := o.logger.With(zap.String("name", torrent.NameString()))
+			if _, err := o.Organize(ctx, torrent); err != nil {
+				logger.Error("failed to organize torrent", zap.Error(err))
+			}
+			extractions, err := o.Extract(ctx, torrent)
+			if err != nil {
+				logger.Error("failed to extract torrent", zap.Error(err))
+			}
+			for _, extraction := range extractions {
+				if o.extractions == nil {
+					continue
+				}
+				select {
+				case o.extractions <- extraction:
+				case <-ctx.Done():
+					return nil
+				}
+			}
+		}
+	}
+}
+
+// locate returns where the torrent's content is and the library directory it belongs in,
+// the directory is empty if no library matches.
+func (o *Organizer) locate(torrent *models.Torrent) (string, string, error) {
+	data := newLayoutData(torrent)
+	// Transmission downloads categorized torrents into a subdirectory named after the category,
+	// torrents with a detected category stay where they were added
+	source := filepath.Join(o.downloadDirectory, strings.ToLower(data.Category), torrent.Name)
+	if _, err := os.Stat(source); errors.Is(err, fs.ErrNotExist) {
+		source = filepath.Join(o.downloadDirectory, torrent.Name)
+	}
+	library, ok := library(o.config.Libraries, data.Category)
+	if !ok {
+		return source, "", nil
+	}
+	directory, err := destination(library, data)
+	if err != nil {
+		return "", "", err
+	}
+	return source, directory, nil
+}
+
+// Organize puts the torrent's files into its library and records their final paths.
+// Torrents without a matching library are left alone, as are archives which will be extracted instead.
+func (o *Organizer) Organize(ctx context.Context, torrent *models.Torrent) ([]*models.OrganizedFile, error) {
+	source, directory, err := o.locate(torrent)
+	if err != nil || directory == "" {
+		return nil, err
+	}
+	files, err := plan(source, directory)
+	if err != nil {
+		return nil, err
+	}
+	if o.config.Extract.Enabled {
+		_, files = findArchives(files)
+	}
+	organized := make([]*models.OrganizedFile, 0, len(files))
+	for _, file := range files {
+		logger := o.logger.With(
+			zap.String("mode", o.config.Mode),
+			zap.String("source", file.source),
+			zap.String("destination", file.destination),
+		)
+		if o.config.DryRun {
+			logger.Info("would organize file")
+			continue
+		}
+		if _, err := os.Stat(file.destination); err == nil {
+			logger.Info("organized file already exists")
+			continue
+		}
+		if err := o.put(file); err != nil {
+			return organized, err
+		}
+		record := &models.OrganizedFile{
+			TorrentID:       torrent.ID,
+			SourcePath:      file.source,
+			DestinationPath: file.destination,
+			Mode:            o.config.Mode,
+		}
+		if err := record.Create(ctx, o.sess); err != nil {
+			return organized, fmt.Errorf("failed recording organized file: %w", err)
+		}
+		logger.Info("organized file")
+		organized = append(organized, record)
+	}
+	return organized, nil
+}
+
+func (o *Organizer) put(file plannedFile) error {
+	if err := os.MkdirAll(filepath.Dir(file.destination), 0o755); err != nil {
+		return fmt.Errorf("failed creating destination directory: %w", err)
+	}
+	switch o.config.Mode {
+	case config.OrganizeModeMove:
+		if err := os.Rename(file.source, file.destination); err != nil {
+			return fmt.Errorf("failed moving file: %w", err)
+		}
+	case config.OrganizeModeHardlink:
+		if err := os.Link(file.source, file.destination); err != nil {
+			return fmt.Errorf("failed linking file: %w", err)
+		}
+	default:
+		return copyFile(file.source, file.destination)
+	}
+	return nil
+}
+
+func copyFile(source, destination string) (err error) {
+	in, err := os.Open(source)
+	if err != nil {
+		return fmt.Errorf("failed opening source file: %w", err)
+	}
+	defer in.Close()
+	out, err := os.OpenFile(destination, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed creating destination file: %w", err)
+	}
+	defer func() {
+		if closeErr := out.Close(); closeErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed closing destination file: %w", closeErr))
+		}
+	}()
+	if _, err := io.Copy(out, in); err != nil {
+		return fmt.Errorf("failed copying file: %w", err)
+	}
+	return nil
+}
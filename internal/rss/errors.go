@@ -0,0 +1,11 @@
+package rss
+
+import "fmt"
+
+type unexpectedStatusError struct {
+	code int
+}
+
+func (u unexpectedStatusError) Error() string {
+	return fmt.Sprintf("unexpected status code: %d", u.code)
+}
@@ -0,0 +1,158 @@
+package rss
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Item is a single entry of an RSS or Atom feed which links to a magnet.
+type Item struct {
+	GUID       string
+	Title      string
+	MagnetLink string
+	// Size is the size of the content in bytes, zero if the feed doesn't say.
+	Size uint64
+}
+
+type rssDocument struct {
+	Items []rssItem `xml:"channel>item"`
+}
+
+type rssItem struct {
+	Title     string `xml:"title"`
+	Link      string `xml:"link"`
+	GUID      string `xml:"guid"`
+	MagnetURI string `xml:"magnetURI"`
+	// ContentLength is used by ezrss style torrent feeds
+	ContentLength string `xml:"contentLength"`
+	Enclosure     struct {
+		URL    string `xml:"url,attr"`
+		Length string `xml:"length,attr"`
+	} `xml:"enclosure"`
+	// Attrs are torznab attributes
+	Attrs []struct {
+		Name  string `xml:"name,attr"`
+		Value string `xml:"value,attr"`
+	} `xml:"attr"`
+}
+
+type atomDocument struct {
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	ID    string `xml:"id"`
+	Title string `xml:"title"`
+	Links []struct {
+		Href   string `xml:"href,attr"`
+		Length string `xml:"length,attr"`
+	} `xml:"link"`
+}
+
+var errUnknownFeedFormat = errors.New("unknown feed format")
+
+// Parse reads an RSS 2.0 or Atom feed, skipping entries without a magnet link.
+func Parse(r io.Reader) ([]Item, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading feed: %w", err)
+	}
+	var root struct {
+		XMLName xml.Name
+	}
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed decoding feed: %w", err)
+	}
+	switch root.XMLName.Local {
+	case "rss":
+		var doc rssDocument
+		if err := xml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed decoding rss feed: %w", err)
+		}
+		return doc.items(), nil
+	case "feed":
+		var doc atomDocument
+		if err := xml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed decoding atom feed: %w", err)
+		}
+		return doc.items(), nil
+	default:
+		return nil, errUnknownFeedFormat
+	}
+}
+
+func (d rssDocument) items() []Item {
+	output := make([]Item, 0, len(d.Items))
+	for _, raw := range d.Items {
+		candidates := []string{raw.MagnetURI, raw.Enclosure.URL, raw.Link}
+		sizes := []string{raw.ContentLength, raw.Enclosure.Length}
+		for _, attr := range raw.Attrs {
+			switch attr.Name {
+			case "magneturl":
+				candidates = append(candidates, attr.Value)
+			case "size":
+				sizes = append(sizes, attr.Value)
+			}
+		}
+		item := Item{
+			GUID:       raw.GUID,
+			Title:      strings.TrimSpace(raw.Title),
+			MagnetLink: firstMagnet(candidates...),
+			Size:       firstSize(sizes...),
+		}
+		if item.GUID == "" {
+			item.GUID = item.MagnetLink
+		}
+		if item.MagnetLink != "" {
+			output = append(output, item)
+		}
+	}
+	return output
+}
+
+func (d atomDocument) items() []Item {
+	output := make([]Item, 0, len(d.Entries))
+	for _, raw := range d.Entries {
+		item := Item{
+			GUID:  raw.ID,
+			Title: strings.TrimSpace(raw.Title),
+		}
+		for _, link := range raw.Links {
+			if magnet := firstMagnet(link.Href); magnet != "" {
+				item.MagnetLink = magnet
+				item.Size = firstSize(link.Length)
+				break
+			}
+		}
+		if item.GUID == "" {
+			item.GUID = item.MagnetLink
+		}
+		if item.MagnetLink != "" {
+			output = append(output, item)
+		}
+	}
+	return output
+}
+
+func firstMagnet(candidates ...string) string {
+	for _, candidate := range candidates {
+		candidate = strings.TrimSpace(candidate)
+		if strings.HasPrefix(candidate, "magnet:") {
+			return candidate
+		}
+	}
+	return ""
+}
+
+func firstSize(candidates ...string) uint64 {
+	for _, candidate := range candidates {
+		if size, err := strconv.ParseUint(strings.TrimSpace(candidate), 10, 64); err == nil && size != 0 {
+			return size
+		}
+	}
+	return 0
+}
@@ -0,0 +1,103 @@
+package rss
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		feed    string
+		want    []Item
+		wantErr bool
+	}{
+		"RSS with magnet URI": {
+			feed: `<?xml version="1.0"?>
+<rss version="2.0" xmlns:torrent="http://xmlns.ezrss.it/0.1/">
+  <channel>
+    <item>
+      <title>Some.Show.S01E02.1080p.WEB-DL.x264-GRP</title>
+      <guid>1</guid>
+      <torrent:contentLength>1024</torrent:contentLength>
+      <torrent:magnetURI><![CDATA[magnet:?xt=urn:btih:1&dn=one]]></torrent:magnetURI>
+    </item>
+    <item>
+      <title>No magnet</title>
+      <guid>2</guid>
+      <link>https://example.com/2.torrent</link>
+    </item>
+  </channel>
+</rss>`,
+			want: []Item{
+				{GUID: "1", Title: "Some.Show.S01E02.1080p.WEB-DL.x264-GRP", MagnetLink: "magnet:?xt=urn:btih:1&dn=one", Size: 1024},
+			},
+		},
+		"RSS with torznab attributes": {
+			feed: `<rss version="2.0" xmlns:torznab="http://torznab.com/schemas/2015/feed">
+  <channel>
+    <item>
+      <title>Movie 2004</title>
+      <link>https://example.com/download</link>
+      <torznab:attr name="size" value="2048"/>
+      <torznab:attr name="magneturl" value="magnet:?xt=urn:btih:2"/>
+    </item>
+  </channel>
+</rss>`,
+			want: []Item{
+				{GUID: "magnet:?xt=urn:btih:2", Title: "Movie 2004", MagnetLink: "magnet:?xt=urn:btih:2", Size: 2048},
+			},
+		},
+		"Atom": {
+			feed: `<feed xmlns="http://www.w3.org/2005/Atom">
+  <entry>
+    <id>urn:3</id>
+    <title> Album </title>
+    <link href="https://example.com/3"/>
+    <link rel="enclosure" href="magnet:?xt=urn:btih:3" length="4096"/>
+  </entry>
+</feed>`,
+			want: []Item{
+				{GUID: "urn:3", Title: "Album", MagnetLink: "magnet:?xt=urn:btih:3", Size: 4096},
+			},
+		},
+		"Unknown format": {
+			feed:    `<html></html>`,
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		testData := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := Parse(strings.NewReader(testData.feed))
+			if (err != nil) != testData.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, testData.wantErr)
+				return
+			}
+			if !testData.wantErr && !reflect.DeepEqual(got, testData.want) {
+				t.Errorf("Parse() = %+v, want %+v", got, testData.want)
+			}
+		})
+	}
+}
+
+func TestEpisodeKey(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		"Some.Show.S01E02.1080p.WEB-DL.x264-GRP": "S01E02",
+		"Some Show s1e2 720p":                    "S01E02",
+		"Some.Show.1x02.HDTV":                    "S01E02",
+		"Some.Movie.2004.1080p":                  "",
+	}
+	for title, want := range tests {
+		testTitle, testWant := title, want
+		t.Run(title, func(t *testing.T) {
+			t.Parallel()
+			if got := EpisodeKey(testTitle); got != testWant {
+				t.Errorf("EpisodeKey() = %q, want %q", got, testWant)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package rss
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/bobcob7/polly-bot/internal/config"
+	"github.com/bobcob7/polly-bot/internal/downloads"
+	"github.com/bobcob7/polly-bot/internal/models"
+	"github.com/upper/db/v4"
+	"go.uber.org/zap"
+)
+
+// Poller periodically fetches all feeds and adds the items matching their rules.
+type Poller struct {
+	logger *zap.Logger
+	config config.RSS
+	sess   db.Session
+	adder  *downloads.Adder
+	client *http.Client
+}
+
+func NewPoller(cfg config.RSS, sess db.Session, adder *downloads.Adder) *Poller {
+	return &Poller{
+		logger: zap.L().With(zap.String("component", "rss")),
+		config: cfg,
+		sess:   sess,
+		adder:  adder,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (p *Poller) Run(ctx context.Context) error {
+	ticker := time.NewTicker(p.config.Period)
+	defer ticker.Stop()
+	for {
+		if err := p.poll(ctx); err != nil {
+			p.logger.Error("failed to poll feeds", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *Poller) poll(ctx context.Context) error {
+	feeds, err := models.GetRSSFeeds(ctx, p.sess)
+	if err != nil {
+		return fmt.Errorf("failed getting feeds: %w", err)
+	}
+	for _, feed := range feeds {
+		// A broken feed shouldn't block the others
+		if err := p.pollFeed(ctx, feed); err != nil {
+			p.logger.Error("failed to poll feed", zap.String("url", feed.URL), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+func (p *Poller) fetch(ctx context.Context, url string) ([]Item, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating request: %w", err)
+	}
+	res, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed fetching feed: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, unexpectedStatusError{res.StatusCode}
+	}
+	return Parse(res.Body)
+}
+
+func (p *Poller) pollFeed(ctx context.Context, feed *models.RSSFeed) error {
+	items, err := p.fetch(ctx, feed.URL)
+	if err != nil {
+		return err
+	}
+	rules, err := p.rules(ctx, feed)
+	if err != nil {
+		return err
+	}
+	guids := make([]string, 0, len(items))
+	for _, item := range items {
+		guids = append(guids, item.GUID)
+	}
+	unseen, err := feed.Unseen(ctx, p.sess, guids...)
+	if err != nil {
+		return fmt.Errorf("failed getting unseen items: %w", err)
+	}
+	unseenSet := make(map[string]struct{}, len(unseen))
+	for _, guid := range unseen {
+		unseenSet[guid] = struct{}{}
+	}
+	for _, item := range items {
+		if _, ok := unseenSet[item.GUID]; !ok {
+			continue
+		}
+		// Only mark seen once, feeds may repeat GUIDs
+		delete(unseenSet, item.GUID)
+		var added, failed bool
+		for _, rule := range rules {
+			ok, err := p.apply(ctx, rule, item)
+			if err != nil {
+				p.logger.Error("failed to apply rule", zap.String("ruleID", rule.ID), zap.String("title", item.Title), zap.Error(err))
+				failed = true
+			}
+			if ok {
+				added = true
+				break
+			}
+		}
+		// Items which failed to add are retried on the next polls, until they run out of attempts
+		if failed && !added {
+			attempts, err := feed.MarkFailed(ctx, p.sess, item.GUID)
+			if err != nil {
+				return fmt.Errorf("failed marking item failed: %w", err)
+			}
+			if attempts < p.config.MaxAttempts {
+				continue
+			}
+			p.logger.Warn("giving up on rss item", zap.String("title", item.Title), zap.Int("attempts", attempts))
+		}
+		if err := feed.MarkSeen(ctx, p.sess, item.GUID); err != nil {
+			return fmt.Errorf("failed marking item seen: %w", err)
+		}
+	}
+	if err := feed.TrimSeen(ctx, p.sess, p.config.HistoryLength); err != nil {
+		return fmt.Errorf("failed trimming seen items: %w", err)
+	}
+	if err := feed.Polled(ctx, p.sess); err != nil {
+		return fmt.Errorf("failed updating feed: %w", err)
+	}
+	return nil
+}
+
+// compiledRule is a rule with its pattern compiled, so it isn't compiled for every item.
+type compiledRule struct {
+	*models.RSSRule
+	pattern *regexp.Regexp
+}
+
+// rules gets the feed's rules, skipping any with an invalid pattern.
+func (p *Poller) rules(ctx context.Context, feed *models.RSSFeed) ([]compiledRule, error) {
+	rawRules, err := models.GetRSSRules(ctx, p.sess, "feed_id", feed.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting rules: %w", err)
+	}
+	rules := make([]compiledRule, 0, len(rawRules))
+	for _, rule := range rawRules {
+		pattern, err := CompilePattern(rule.Pattern)
+		if err != nil {
+			p.logger.Error("skipping rule", zap.String("ruleID", rule.ID), zap.Error(err))
+			continue
+		}
+		rules = append(rules, compiledRule{RSSRule: rule, pattern: pattern})
+	}
+	return rules, nil
+}
+
+// apply adds the item if it matches the rule, returning whether it was added.
+func (p *Poller) apply(ctx context.Context, rule compiledRule, item Item) (bool, error) {
+	if !Matches(rule.RSSRule, rule.pattern, item) {
+		return false, nil
+	}
+	var episode string
+	if rule.DedupeEpisodes {
+		episode = EpisodeKey(item.Title)
+		if episode != "" {
+			exists, err := rule.HasEpisode(ctx, p.sess, episode)
+			if err != nil {
+				return false, fmt.Errorf("failed checking episode: %w", err)
+			}
+			if exists {
+				p.logger.Debug("skipping duplicate episode", zap.String("title", item.Title), zap.String("episode", episode))
+				return false, nil
+			}
+		}
+	}
+	name := item.Title
+	if len(name) > 255 {
+		name = name[:255]
+	}
+	p.logger.Info("adding rss item", zap.String("ruleID", rule.ID), zap.String("title", item.Title))
+	torrent, err := p.adder.Add(ctx, downloads.Request{
+		MagnetLink:   item.MagnetLink,
+		FriendlyName: name,
+		Category:     rule.Category,
+		RecipientID:  rule.CreatedBy,
+		ChannelID:    rule.ChannelID,
+		RequestedBy:  rule.CreatedBy,
+		Unmetered:    true,
+		GuildID:      rule.GuildID,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed adding item: %w", err)
+	}
+	if episode != "" {
+		if err := rule.AddEpisode(ctx, p.sess, episode, torrent.ID); err != nil {
+			return true, fmt.Errorf("failed recording episode: %w", err)
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,45 @@
+package rss
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/bobcob7/polly-bot/internal/models"
+	"github.com/bobcob7/polly-bot/internal/torrent"
+)
+
+// CompilePattern compiles a rule pattern, which always matches case insensitively.
+func CompilePattern(pattern string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	return re, nil
+}
+
+// Matches returns whether the item passes the rule's pattern and size limits.
+// Size limits are ignored for items of unknown size.
+func Matches(rule *models.RSSRule, pattern *regexp.Regexp, item Item) bool {
+	if !pattern.MatchString(item.Title) {
+		return false
+	}
+	if item.Size != 0 {
+		if rule.MinSize != 0 && item.Size < rule.MinSize {
+			return false
+		}
+		if rule.MaxSize != 0 && item.Size > rule.MaxSize {
+			return false
+		}
+	}
+	return true
+}
+
+// EpisodeKey returns a normalized season and episode like "S01E02" from a release title,
+// or an empty string if the title doesn't name an episode.
+func EpisodeKey(title string) string {
+	release := torrent.ParseRelease(title)
+	if release.FirstEpisode == 0 {
+		return ""
+	}
+	return fmt.Sprintf("S%02dE%02d", release.Season, release.FirstEpisode)
+}
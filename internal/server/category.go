@@ -0,0 +1,32 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bobcob7/polly-bot/internal/downloads"
+	"github.com/bobcob7/polly-bot/internal/models"
+	"github.com/bobcob7/polly-bot/internal/torrent"
+	"github.com/bobcob7/transmission-rpc"
+	"go.uber.org/zap"
+)
+
+// detectCategory assigns a category to torrents added without one once their file list is known.
+func (s *Server) detectCategory(ctx context.Context, t *models.Torrent, files []transmission.File) error {
+	if len(files) == 0 || (t.TorrentMetadata != nil && len(t.Categories) != 0) {
+		return nil
+	}
+	torrentFiles := make([]torrent.File, 0, len(files))
+	for _, file := range files {
+		torrentFiles = append(torrentFiles, torrent.File{Path: file.Name, Length: file.Length})
+	}
+	category := downloads.DetectCategory(t.Name, torrentFiles)
+	if category == "" {
+		return nil
+	}
+	s.logger.Info("detected torrent category", zap.String("name", t.NameString()), zap.String("category", category))
+	if err := t.AddCategory(ctx, s.sess, category); err != nil {
+		return fmt.Errorf("failed setting detected category: %w", err)
+	}
+	return nil
+}
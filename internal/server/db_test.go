@@ -0,0 +1,87 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/bobcob7/transmission-rpc"
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/upper/db/v4"
+	"github.com/upper/db/v4/adapter/postgresql"
+)
+
+// testDatabaseEnv is a postgres URL for tests which need a database, they are skipped without one.
+const testDatabaseEnv = "POLLY_TEST_DATABASE_URL"
+
+var migrateOnce sync.Once
+
+// testSession connects to the test database, migrating it on first use.
+func testSession(t *testing.T) db.Session {
+	t.Helper()
+	url := os.Getenv(testDatabaseEnv)
+	if url == "" {
+		t.Skipf("%s is not set", testDatabaseEnv)
+	}
+	var migrateErr error
+	migrateOnce.Do(func() {
+		driver, err := iofs.New(os.DirFS("../.."), "migrations")
+		if err != nil {
+			migrateErr = err
+			return
+		}
+		m, err := migrate.NewWithSourceInstance("iofs", driver, url)
+		if err != nil {
+			migrateErr = err
+			return
+		}
+		defer m.Close()
+		if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+			migrateErr = err
+		}
+	})
+	if migrateErr != nil {
+		t.Fatalf("failed migrating test database: %v", migrateErr)
+	}
+	settings, err := postgresql.ParseURL(url)
+	if err != nil {
+		t.Fatalf("invalid %s: %v", testDatabaseEnv, err)
+	}
+	sess, err := postgresql.Open(settings)
+	if err != nil {
+		t.Fatalf("failed connecting to test database: %v", err)
+	}
+	t.Cleanup(func() { sess.Close() })
+	return sess
+}
+
+// fakeTorrentClient serves fixed torrents and records what was stopped and removed.
+type fakeTorrentClient struct {
+	torrents []transmission.Torrent
+
+	lock    sync.Mutex
+	stopped []int
+	removed []int
+}
+
+func (f *fakeTorrentClient) GetTorrents(ctx context.Context, ids ...int) ([]transmission.Torrent, error) {
+	return f.torrents, nil
+}
+
+func (f *fakeTorrentClient) StopTorrents(ctx context.Context, ids ...int) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.stopped = append(f.stopped, ids...)
+	return nil
+}
+
+func (f *fakeTorrentClient) RemoveTorrents(ctx context.Context, deleteLocalData bool, ids ...int) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.removed = append(f.removed, ids...)
+	return nil
+}
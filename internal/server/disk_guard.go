@@ -0,0 +1,66 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/bobcob7/polly-bot/internal/config"
+	"github.com/bobcob7/polly-bot/internal/downloads"
+	"github.com/bobcob7/polly-bot/internal/models"
+	"go.uber.org/zap"
+)
+
+// guardDiskSpace pauses or rejects downloading torrents which don't fit on the disk, once their size is known.
+// Each torrent is only checked once.
+func (s *Server) guardDiskSpace(ctx context.Context, torrent *models.Torrent) error {
+	if !s.diskGuard.Enabled() || torrent.CompletedAt != nil || torrent.TotalSize == 0 {
+		return nil
+	}
+	if _, checked := torrent.Label(models.DiskGuardLabel); checked {
+		return nil
+	}
+	var remaining uint64
+	if torrent.Downloaded < torrent.TotalSize {
+		remaining = torrent.TotalSize - torrent.Downloaded
+	}
+	checkErr := s.diskGuard.Check(ctx, remaining)
+	if checkErr == nil {
+		if err := torrent.SetLabel(ctx, s.sess, models.DiskGuardLabel, downloads.DiskGuardOK); err != nil {
+			return fmt.Errorf("failed setting disk guard label: %w", err)
+		}
+		return nil
+	}
+	if !downloads.IsInsufficientSpace(checkErr) {
+		return fmt.Errorf("failed checking disk space: %w", checkErr)
+	}
+	id, err := strconv.Atoi(torrent.ID)
+	if err != nil {
+		return fmt.Errorf("invalid torrent id %q: %w", torrent.ID, err)
+	}
+	s.logger.Warn("torrent doesn't fit on disk",
+		zap.String("name", torrent.NameString()),
+		zap.String("action", s.diskGuard.Action()),
+		zap.Error(checkErr),
+	)
+	var label string
+	switch s.diskGuard.Action() {
+	case config.DiskGuardActionPause:
+		if err := s.tx.StopTorrents(ctx, id); err != nil {
+			return fmt.Errorf("failed pausing torrent: %w", err)
+		}
+		label = downloads.DiskGuardPaused + checkErr.Error()
+	case config.DiskGuardActionReject:
+		if err := s.tx.RemoveTorrents(ctx, true, id); err != nil {
+			return fmt.Errorf("failed removing torrent: %w", err)
+		}
+		if err := torrent.MarkDeleted(ctx, s.sess); err != nil {
+			return fmt.Errorf("failed marking torrent deleted: %w", err)
+		}
+		label = downloads.DiskGuardRejected + checkErr.Error()
+	}
+	if err := torrent.SetLabel(ctx, s.sess, models.DiskGuardLabel, label); err != nil {
+		return fmt.Errorf("failed setting disk guard label: %w", err)
+	}
+	return nil
+}
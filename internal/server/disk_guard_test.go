@@ -0,0 +1,95 @@
+package server
+
+import (
+	"context"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bobcob7/polly-bot/internal/config"
+	"github.com/bobcob7/polly-bot/internal/downloads"
+	"github.com/bobcob7/polly-bot/internal/models"
+	"github.com/bobcob7/transmission-rpc"
+)
+
+func TestServer_scrapeDiskGuard(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		size        uint64
+		action      string
+		wantStopped bool
+		wantRemoved bool
+		wantLabel   string
+	}{
+		"Fits": {
+			size:      50,
+			action:    config.DiskGuardActionPause,
+			wantLabel: downloads.DiskGuardOK,
+		},
+		"Paused": {
+			size:        500,
+			action:      config.DiskGuardActionPause,
+			wantStopped: true,
+			wantLabel:   downloads.DiskGuardPaused,
+		},
+		"Rejected": {
+			size:        500,
+			action:      config.DiskGuardActionReject,
+			wantRemoved: true,
+			wantLabel:   downloads.DiskGuardRejected,
+		},
+	}
+	for name, tt := range tests {
+		testData := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			sess := testSession(t)
+			//nolint: gosec
+			id := rand.Intn(1 << 30)
+			tx := &fakeTorrentClient{
+				torrents: []transmission.Torrent{{
+					ID:           id,
+					Name:         "Big download",
+					AddedDate:    uint64(time.Now().Unix()),
+					Status:       4,
+					MagnetLink:   "magnet:?xt=urn:btih:" + strconv.Itoa(id),
+					SizeWhenDone: testData.size,
+				}},
+			}
+			guard := downloads.NewDiskGuard(config.TransmissionDiskGuard{
+				Enabled: true,
+				Reserve: 10,
+				Action:  testData.action,
+			}, func(context.Context) (uint64, error) {
+				return 100, nil
+			})
+			s := New(config.New(), sess, tx, guard, nil)
+			if err := s.scrape(context.Background()); err != nil {
+				t.Fatalf("Server.scrape() error = %v", err)
+			}
+			var wantStopped, wantRemoved []int
+			if testData.wantStopped {
+				wantStopped = []int{id}
+			}
+			if testData.wantRemoved {
+				wantRemoved = []int{id}
+			}
+			if !reflect.DeepEqual(tx.stopped, wantStopped) {
+				t.Errorf("Server.scrape() stopped %v, want %v", tx.stopped, wantStopped)
+			}
+			if !reflect.DeepEqual(tx.removed, wantRemoved) {
+				t.Errorf("Server.scrape() removed %v, want %v", tx.removed, wantRemoved)
+			}
+			torrent := &models.Torrent{ID: strconv.Itoa(id)}
+			if err := torrent.Get(context.Background(), sess); err != nil {
+				t.Fatal(err)
+			}
+			if label, _ := torrent.Label(models.DiskGuardLabel); !strings.HasPrefix(label, testData.wantLabel) {
+				t.Errorf("Server.scrape() labelled %q, want %q", label, testData.wantLabel)
+			}
+		})
+	}
+}
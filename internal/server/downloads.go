@@ -0,0 +1,118 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bobcob7/polly-bot/internal/models"
+	downloadsv1 "github.com/bobcob7/polly-bot/pkg/proto/downloads/v1"
+	"github.com/bufbuild/connect-go"
+	"github.com/upper/db/v4"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (s *Server) GetDownloads(ctx context.Context, req *connect.Request[downloadsv1.GetDownloadsRequest]) (*connect.Response[downloadsv1.GetDownloadsResponse], error) {
+	cond := db.Cond{}
+	if len(req.Msg.Ids) != 0 {
+		ids := make([]int64, 0, len(req.Msg.Ids))
+		for _, rawID := range req.Msg.Ids {
+			id, err := strconv.ParseInt(rawID, 10, 64)
+			if err != nil {
+				return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("invalid id %q: %w", rawID, err))
+			}
+			ids = append(ids, id)
+		}
+		cond["id IN"] = ids
+	}
+	if len(req.Msg.Statuses) != 0 {
+		statuses := make([]int, 0, len(req.Msg.Statuses))
+		for _, status := range req.Msg.Statuses {
+			statuses = append(statuses, statusFromProto(status))
+		}
+		cond["status IN"] = statuses
+	}
+	if len(req.Msg.RequestedBy) != 0 {
+		cond["requested_by IN"] = req.Msg.RequestedBy
+	}
+	if len(req.Msg.GuildIds) != 0 {
+		cond["guild_id IN"] = append([]string{""}, req.Msg.GuildIds...)
+	}
+	torrents, err := models.GetTorrents(ctx, s.sess, 0, cond)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	res := &downloadsv1.GetDownloadsResponse{
+		Downloads: make([]*downloadsv1.Download, 0, len(torrents)),
+	}
+	for _, torrent := range torrents {
+		stats, err := torrent.GetStats(ctx, s.sess, s.scraperConfig.RateWindow)
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+		res.Downloads = append(res.Downloads, downloadToProto(torrent, stats))
+	}
+	return connect.NewResponse(res), nil
+}
+
+// Transmission statuses start at zero for stopped, while the proto reserves zero for unspecified.
+func statusToProto(status int) downloadsv1.DownloadStatus {
+	return downloadsv1.DownloadStatus(status + 1)
+}
+
+func statusFromProto(status downloadsv1.DownloadStatus) int {
+	return int(status) - 1
+}
+
+func categoryToProto(category string) downloadsv1.DownloadCategory {
+	name := "DOWNLOAD_CATEGORY_" + strings.ReplaceAll(strings.ToUpper(category), " ", "_")
+	return downloadsv1.DownloadCategory(downloadsv1.DownloadCategory_value[name])
+}
+
+func downloadToProto(torrent *models.Torrent, stats models.TorrentStats) *downloadsv1.Download {
+	output := &downloadsv1.Download{
+		Id: torrent.ID,
+		Metadata: &downloadsv1.DownloadMetadata{
+			Name:      torrent.NameString(),
+			CreatedAt: timestamppb.New(torrent.CreatedAt),
+		},
+		Status:     statusToProto(torrent.Status),
+		MagnetLink: torrent.MagnetLink,
+		Size:       torrent.TotalSize,
+		Downloaded: torrent.Downloaded,
+		Uploaded:   torrent.Uploaded,
+		Ratio:      torrent.Ratio(),
+		Stats: &downloadsv1.DownloadStats{
+			DownloadRate: stats.DownloadRate,
+			UploadRate:   stats.UploadRate,
+			Uploaded:     stats.Uploaded,
+			Window:       durationpb.New(stats.Window),
+		},
+	}
+	if torrent.TotalSize != 0 {
+		output.Progress = float64(torrent.Downloaded) / float64(torrent.TotalSize)
+	}
+	if stats.ETA != 0 {
+		output.Stats.Eta = durationpb.New(stats.ETA)
+	}
+	if torrent.StartedAt != nil {
+		output.Metadata.StartedAt = timestamppb.New(*torrent.StartedAt)
+	}
+	if torrent.CompletedAt != nil {
+		output.Metadata.CompletedAt = timestamppb.New(*torrent.CompletedAt)
+	}
+	if torrent.TorrentMetadata != nil {
+		output.Metadata.Labels = torrent.Labels
+		output.Metadata.RequestedBy = torrent.RequestedBy
+		output.Metadata.GuildId = torrent.GuildID
+		for _, category := range torrent.Categories {
+			output.Metadata.Categories = append(output.Metadata.Categories, categoryToProto(category))
+		}
+		if torrent.DeletedAt != nil {
+			output.Metadata.DeletedAt = timestamppb.New(*torrent.DeletedAt)
+		}
+	}
+	return output
+}
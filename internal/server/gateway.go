@@ -0,0 +1,127 @@
+package server
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	downloadsv1 "github.com/bobcob7/polly-bot/pkg/proto/downloads/v1"
+	"github.com/bobcob7/polly-bot/pkg/proto/downloads/v1/downloadsv1connect"
+	"github.com/bufbuild/connect-go"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+//go:embed openapi.yaml
+var openAPI []byte
+
+var errMethodNotAllowed = errors.New("method not allowed")
+
+type unknownStatusError struct {
+	status string
+}
+
+func (u unknownStatusError) Error() string {
+	return fmt.Sprintf("unknown status: %q", u.status)
+}
+
+// gateway serves REST routes for DownloadService, by rewriting them into Connect protocol
+// JSON requests to the RPC handler, so they go through the same interceptors as RPCs.
+// DeleteDownload isn't mapped until it is implemented.
+type gateway struct {
+	rpc http.Handler
+}
+
+func newGateway(rpc http.Handler) http.Handler {
+	g := &gateway{rpc: rpc}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/downloads", g.downloads)
+	mux.HandleFunc("/v1/openapi.yaml", serveOpenAPI)
+	return mux
+}
+
+func serveOpenAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		methodNotAllowed(w, http.MethodGet, http.MethodHead)
+		return
+	}
+	w.Header().Set("Content-Type", "application/yaml")
+	_, _ = w.Write(openAPI)
+}
+
+// downloads lists downloads, filtered by the repeated id, status, requested_by and guild_id query parameters.
+func (g *gateway) downloads(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	query := r.URL.Query()
+	req := &downloadsv1.GetDownloadsRequest{
+		Ids:         query["id"],
+		RequestedBy: query["requested_by"],
+		GuildIds:    query["guild_id"],
+	}
+	for _, status := range query["status"] {
+		value, ok := parseStatus(status)
+		if !ok {
+			writeError(w, http.StatusBadRequest, connect.NewError(connect.CodeInvalidArgument, unknownStatusError{status}))
+			return
+		}
+		req.Statuses = append(req.Statuses, value)
+	}
+	g.call(w, r, downloadsv1connect.DownloadServiceGetDownloadsProcedure, req)
+}
+
+// parseStatus accepts the full enum name, or it without the DOWNLOAD_STATUS_ prefix.
+func parseStatus(status string) (downloadsv1.DownloadStatus, bool) {
+	name := strings.ToUpper(status)
+	if !strings.HasPrefix(name, "DOWNLOAD_STATUS_") {
+		name = "DOWNLOAD_STATUS_" + name
+	}
+	value, ok := downloadsv1.DownloadStatus_value[name]
+	return downloadsv1.DownloadStatus(value), ok
+}
+
+// call forwards the request to the procedure, the Connect protocol's unary JSON responses
+// and errors are already plain JSON, so they are passed through as they are.
+func (g *gateway) call(w http.ResponseWriter, r *http.Request, procedure string, msg proto.Message) {
+	body, err := protojson.Marshal(msg)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, connect.NewError(connect.CodeInternal, err))
+		return
+	}
+	req := r.Clone(r.Context())
+	req.Method = http.MethodPost
+	req.URL.Path = procedure
+	req.URL.RawPath = ""
+	req.URL.RawQuery = ""
+	req.RequestURI = procedure
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Connect-Protocol-Version", "1")
+	req.Header.Del("Content-Encoding")
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+	g.rpc.ServeHTTP(w, req)
+}
+
+func methodNotAllowed(w http.ResponseWriter, methods ...string) {
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	writeError(w, http.StatusMethodNotAllowed, connect.NewError(connect.CodeUnimplemented, errMethodNotAllowed))
+}
+
+// writeError writes err the way the Connect protocol does, so every error from the gateway has the same shape.
+func writeError(w http.ResponseWriter, status int, err *connect.Error) {
+	var body bytes.Buffer
+	_ = json.NewEncoder(&body).Encode(struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}{err.Code().String(), err.Message()})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(body.Bytes())
+}
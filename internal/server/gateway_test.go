@@ -0,0 +1,90 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	downloadsv1 "github.com/bobcob7/polly-bot/pkg/proto/downloads/v1"
+	"github.com/bobcob7/polly-bot/pkg/proto/downloads/v1/downloadsv1connect"
+	"github.com/bufbuild/connect-go"
+)
+
+// echoDownloads responds with the IDs and statuses it was asked for, so requests can be checked.
+type echoDownloads struct {
+	downloadsv1connect.UnimplementedDownloadServiceHandler
+}
+
+func (echoDownloads) GetDownloads(_ context.Context, req *connect.Request[downloadsv1.GetDownloadsRequest]) (*connect.Response[downloadsv1.GetDownloadsResponse], error) {
+	res := &downloadsv1.GetDownloadsResponse{}
+	for _, id := range req.Msg.Ids {
+		res.Downloads = append(res.Downloads, &downloadsv1.Download{Id: id})
+	}
+	for _, status := range req.Msg.Statuses {
+		res.Downloads = append(res.Downloads, &downloadsv1.Download{Status: status})
+	}
+	return connect.NewResponse(res), nil
+}
+
+func Test_gateway(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		method     string
+		target     string
+		wantStatus int
+		wantBody   string
+	}{
+		"List": {
+			method:     http.MethodGet,
+			target:     "/v1/downloads?id=1&status=seed&status=DOWNLOAD_STATUS_STOPPED",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"downloads":[{"id":"1"},{"status":"DOWNLOAD_STATUS_SEED"},{"status":"DOWNLOAD_STATUS_STOPPED"}]}`,
+		},
+		"Unknown status": {
+			method:     http.MethodGet,
+			target:     "/v1/downloads?status=finished",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"code":"invalid_argument","message":"unknown status: \"finished\""}`,
+		},
+		"Wrong method": {
+			method:     http.MethodPost,
+			target:     "/v1/downloads",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		"Delete isn't mapped": {
+			method:     http.MethodDelete,
+			target:     "/v1/downloads/5",
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	_, rpc := downloadsv1connect.NewDownloadServiceHandler(echoDownloads{})
+	handler := newGateway(rpc)
+	for name, tt := range tests {
+		testData := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(testData.method, testData.target, nil))
+			if recorder.Code != testData.wantStatus {
+				t.Errorf("gateway status = %d, want %d: %s", recorder.Code, testData.wantStatus, recorder.Body.String())
+			}
+			if testData.wantBody == "" {
+				return
+			}
+			// protojson output isn't stable, so compare the decoded bodies
+			var got, want interface{}
+			if err := json.Unmarshal(recorder.Body.Bytes(), &got); err != nil {
+				t.Fatalf("gateway body %s isn't JSON: %v", recorder.Body.String(), err)
+			}
+			if err := json.Unmarshal([]byte(testData.wantBody), &want); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("gateway body = %s, want %s", recorder.Body.String(), testData.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// HandleOps serves handler on the ops listener, it must be called before Run.
+// Ops routes skip RPC auth, so they should only expose health checks and metrics.
+func (s *Server) HandleOps(pattern string, handler http.Handler) {
+	s.opsRoutes[pattern] = handler
+}
+
+// RunOps serves the ops routes until ctx is done, then waits for in-flight requests to finish.
+func (s *Server) RunOps(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.opsConfig.Address)
+	if err != nil {
+		return fmt.Errorf("failed to listen for ops: %w", err)
+	}
+	mux := http.NewServeMux()
+	for pattern, handler := range s.opsRoutes {
+		mux.Handle(pattern, handler)
+	}
+	server := &http.Server{
+		Handler:      mux,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 10 * time.Second,
+	}
+	shutdownErr := make(chan error, 1)
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, done := context.WithTimeout(context.Background(), s.shutdownTimeout)
+		defer done()
+		shutdownErr <- server.Shutdown(shutdownCtx)
+	}()
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to serve ops: %w", err)
+	}
+	if err := <-shutdownErr; err != nil {
+		return fmt.Errorf("failed to shutdown ops: %w", err)
+	}
+	return nil
+}
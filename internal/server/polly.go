@@ -6,76 +6,185 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/bobcob7/polly-bot/internal/auth"
 	"github.com/bobcob7/polly-bot/internal/config"
+	"github.com/bobcob7/polly-bot/internal/downloads"
+	"github.com/bobcob7/polly-bot/internal/metrics"
 	"github.com/bobcob7/polly-bot/internal/models"
+	"github.com/bobcob7/polly-bot/internal/tracing"
 	downloadsv1 "github.com/bobcob7/polly-bot/pkg/proto/downloads/v1"
 	"github.com/bobcob7/polly-bot/pkg/proto/downloads/v1/downloadsv1connect"
 	"github.com/bobcob7/transmission-rpc"
 	"github.com/bufbuild/connect-go"
 	"github.com/upper/db/v4"
 	"go.uber.org/zap"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"golang.org/x/sync/errgroup"
 )
 
+var tracer = tracing.Tracer("internal/server")
+
 type Server struct {
 	downloadsv1connect.UnimplementedDownloadServiceHandler
 
 	logger            *zap.Logger
 	config            config.GRPC
+	scraperConfig     config.TransmissionScraper
+	stalledConfig     config.TransmissionStalled
+	seedingPolicies   []config.SeedingPolicy
+	diskGuard         *downloads.DiskGuard
+	quotas            *downloads.Quotas
 	sess              db.Session
-	tx                *transmission.Client
-	completedTorrents chan<- *models.Torrent
+	tx                TorrentClient
+	completedTorrents []*subscriber
+	stalledTorrents   *subscriber
+	lastSampled       map[string]time.Time
+	lastPruned        time.Time
+	handlerOptions    []connect.HandlerOption
+	shutdownTimeout   time.Duration
+	routes            map[string]http.Handler
+	opsConfig         config.Ops
+	opsRoutes         map[string]http.Handler
+	lastScraped       atomic.Int64
+
+	notifyLock sync.Mutex
+	// notifying counts the subscribers each completed torrent hasn't been delivered to yet.
+	notifying map[string]int
 }
 
 var _ downloadsv1connect.DownloadServiceHandler = &Server{}
 
+// Run serves RPCs and the ops routes and scrapes transmission until ctx is done or any of them fails.
 func (s *Server) Run(ctx context.Context) error {
-	err := make(chan error, 2)
-	go func() {
-		err <- s.RunGRPC(ctx)
-	}()
-	go func() {
-		err <- s.RunScraper(ctx)
-	}()
-	return <-err
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.Go(func() error {
+		return s.RunGRPC(groupCtx)
+	})
+	group.Go(func() error {
+		return s.RunOps(groupCtx)
+	})
+	group.Go(func() error {
+		return s.RunScraper(groupCtx)
+	})
+	subscribers := make([]*subscriber, 0, len(s.completedTorrents)+1)
+	subscribers = append(subscribers, s.completedTorrents...)
+	if s.stalledTorrents != nil {
+		subscribers = append(subscribers, s.stalledTorrents)
+	}
+	for _, sub := range subscribers {
+		sub := sub
+		group.Go(func() error {
+			return sub.run(groupCtx)
+		})
+	}
+	return group.Wait()
 }
 
+// RunGRPC serves RPCs until ctx is done, then waits for in-flight requests to finish.
 func (s *Server) RunGRPC(ctx context.Context) error {
+	tlsConfig, err := s.tlsConfig()
+	if err != nil {
+		return err
+	}
 	listener, err := net.Listen("tcp", s.config.Address)
 	if err != nil {
 		return fmt.Errorf("failed to listen: %w", err)
 	}
 	mux := http.NewServeMux()
-	mux.Handle(downloadsv1connect.NewDownloadServiceHandler(s))
+	downloadsPath, downloadsHandler := downloadsv1connect.NewDownloadServiceHandler(s, s.handlerOptions...)
+	mux.Handle(downloadsPath, withWriteTimeout(downloadsHandler, unaryWriteTimeout))
+	mux.Handle("/v1/", withWriteTimeout(newGateway(downloadsHandler), unaryWriteTimeout))
+	// Routes may stream, such as health watches, so they don't get a write timeout
+	for pattern, handler := range s.routes {
+		mux.Handle(pattern, handler)
+	}
+	var handler http.Handler = auth.Middleware(mux)
+	http2Server := &http2.Server{}
+	if tlsConfig == nil && s.config.H2C {
+		// gRPC clients need HTTP/2, which is otherwise only negotiated over TLS
+		handler = h2c.NewHandler(handler, http2Server)
+	}
 	server := &http.Server{
-		Handler:      mux,
-		ReadTimeout:  10 * time.Second,
-		WriteTimeout: 10 * time.Second,
+		Handler:     handler,
+		ReadTimeout: 10 * time.Second,
+		TLSConfig:   tlsConfig,
+	}
+	// Also lets Shutdown wait for requests on HTTP/2 connections
+	if err := http2.ConfigureServer(server, http2Server); err != nil {
+		return fmt.Errorf("failed to configure HTTP/2: %w", err)
+	}
+	shutdownErr := make(chan error, 1)
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, done := context.WithTimeout(context.Background(), s.shutdownTimeout)
+		defer done()
+		shutdownErr <- server.Shutdown(shutdownCtx)
+	}()
+	if tlsConfig != nil {
+		// The certificate comes from tlsConfig, so it can be reloaded
+		err = server.ServeTLS(listener, "", "")
+	} else {
+		err = server.Serve(listener)
 	}
-	if err := server.Serve(listener); err != nil {
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
 		return fmt.Errorf("failed to server: %w", err)
 	}
+	if err := <-shutdownErr; err != nil {
+		return fmt.Errorf("failed to shutdown: %w", err)
+	}
 	return nil
 }
 
+// unaryWriteTimeout limits how long unary RPCs can take to write their response.
+const unaryWriteTimeout = 10 * time.Second
+
+// withWriteTimeout sets a write deadline for each request,
+// rather than the server's WriteTimeout which would also end streams.
+func withWriteTimeout(handler http.Handler, timeout time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Responses which don't support deadlines are left without one
+		_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(timeout))
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// RunScraper scrapes transmission until ctx is done, a scrape in progress is given
+// the shutdown timeout to finish, so it isn't interrupted halfway through.
 func (s *Server) RunScraper(ctx context.Context) error {
-	const minPeriod = time.Second * 2
-	const maxPeriod = time.Minute * 5
+	minPeriod := s.scraperConfig.MinPeriod
+	maxPeriod := s.scraperConfig.MaxPeriod
 	var currentPeriod = minPeriod
+	if err := s.renotify(ctx); err != nil {
+		s.logger.Error("failed to renotify completed torrents", zap.Error(err))
+	}
+	timer := time.NewTimer(0)
+	defer timer.Stop()
 	for {
 		select {
 		case <-ctx.Done():
-			if err := ctx.Err(); err != nil {
-				return fmt.Errorf("context error: %w", err)
-			}
 			return nil
-		default:
+		case <-timer.C:
+		}
+		scrapeCtx, done := drainContext(ctx, s.shutdownTimeout)
+		scrapeCtx, span := tracer.Start(scrapeCtx, "scrape")
+		start := time.Now()
+		err := s.scrape(scrapeCtx)
+		metrics.ScrapeDuration.Observe(time.Since(start).Seconds())
+		tracing.End(span, err)
+		done()
+		if err == nil {
+			s.lastScraped.Store(time.Now().UnixNano())
 		}
-		err := s.scrape(ctx)
 		// Adjust scrape period
 		if err != nil {
 			s.logger.Error("failed to scrape", zap.Error(err))
+			metrics.ScrapeFailures.Inc()
 			currentPeriod *= 2
 			if currentPeriod > maxPeriod {
 				currentPeriod = maxPeriod
@@ -86,49 +195,243 @@ func (s *Server) RunScraper(ctx context.Context) error {
 				currentPeriod = minPeriod
 			}
 		}
-		time.Sleep(currentPeriod)
+		metrics.ScrapePeriod.Set(currentPeriod.Seconds())
+		timer.Reset(currentPeriod)
 	}
 }
 
+// drainContext returns a context that is only cancelled once grace has passed after ctx is done.
+func drainContext(ctx context.Context, grace time.Duration) (context.Context, context.CancelFunc) {
+	drainCtx, cancel := context.WithCancel(context.Background())
+	go func() {
+		select {
+		case <-drainCtx.Done():
+			return
+		case <-ctx.Done():
+		}
+		timer := time.NewTimer(grace)
+		defer timer.Stop()
+		select {
+		case <-drainCtx.Done():
+		case <-timer.C:
+			cancel()
+		}
+	}()
+	return drainCtx, cancel
+}
+
 func (s *Server) scrape(ctx context.Context) error {
 	torrents, err := s.tx.GetTorrents(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to scrape torrents from transmission: %w", err)
 	}
 	s.logger.Debug("scraped torrents from transmission", zap.Int("num_torrents", len(torrents)))
+	recordTorrents(torrents)
+	now := time.Now()
+	seen := make(map[string]bool, len(torrents))
 	for _, torrent := range torrents {
 		newTorrent := models.FromTransmission(torrent)
+		seen[newTorrent.ID] = true
 		completed, err := newTorrent.Set(ctx, s.sess)
 		if err != nil {
 			return fmt.Errorf("failed setting torrent in db: %w", err)
 		}
-		if completed && s.completedTorrents != nil {
-			s.completedTorrents <- newTorrent
+		// One torrent failing is retried next scrape, rather than holding up the others
+		if err := s.process(ctx, newTorrent, torrent.Files, now); err != nil {
+			s.logger.Error("failed to process torrent", zap.Error(err), zap.String("name", newTorrent.NameString()))
+		}
+		if completed {
+			if err := s.notify(ctx, newTorrent); err != nil {
+				s.logger.Error("failed to notify completed torrent", zap.Error(err), zap.String("name", newTorrent.NameString()))
+			}
+		}
+	}
+	// Forget torrents which have been removed from transmission
+	for id := range s.lastSampled {
+		if !seen[id] {
+			delete(s.lastSampled, id)
 		}
 	}
+	if now.Sub(s.lastPruned) >= s.scraperConfig.DownsampleResolution {
+		if err := models.PruneTorrentSamples(ctx, s.sess, s.scraperConfig.SampleRetention, s.scraperConfig.DownsampleAfter, s.scraperConfig.DownsampleResolution); err != nil {
+			return fmt.Errorf("failed pruning torrent samples: %w", err)
+		}
+		s.lastPruned = now
+	}
 	return nil
 }
 
-func New(cfg *config.Config, sess db.Session, tx *transmission.Client) *Server {
-	return &Server{
-		logger:            zap.L(),
-		tx:                tx,
-		sess:              sess,
-		config:            cfg.GRPC,
-		completedTorrents: nil,
+// recordTorrents sets the torrent metrics to the state of the scraped torrents.
+func recordTorrents(torrents []transmission.Torrent) {
+	var downloaded, uploaded uint64
+	statuses := make(map[string]int)
+	for _, torrent := range torrents {
+		downloaded += torrent.DownloadedEver
+		uploaded += torrent.UploadedEver
+		status := strings.TrimPrefix(statusToProto(torrent.Status).String(), "DOWNLOAD_STATUS_")
+		statuses[strings.ToLower(status)]++
+	}
+	metrics.Torrents.Reset()
+	for status, count := range statuses {
+		metrics.Torrents.WithLabelValues(status).Set(float64(count))
+	}
+	metrics.DownloadedBytes.Set(float64(downloaded))
+	metrics.UploadedBytes.Set(float64(uploaded))
+}
+
+// sample records the torrent's progress if the last sample is older than the sample resolution.
+func (s *Server) sample(ctx context.Context, torrent *models.Torrent, now time.Time) (bool, error) {
+	if last, ok := s.lastSampled[torrent.ID]; ok && now.Sub(last) < s.scraperConfig.SampleResolution {
+		return false, nil
+	}
+	if err := torrent.Sample(now).Create(ctx, s.sess); err != nil {
+		return false, fmt.Errorf("failed sampling torrent: %w", err)
 	}
+	s.lastSampled[torrent.ID] = now
+	return true, nil
 }
 
+// TorrentClient is the subset of transmission calls the server makes, *tracing.Transmission implements it.
+type TorrentClient interface {
+	GetTorrents(ctx context.Context, ids ...int) ([]transmission.Torrent, error)
+	StopTorrents(ctx context.Context, ids ...int) error
+	RemoveTorrents(ctx context.Context, deleteLocalData bool, ids ...int) error
+}
+
+func New(cfg *config.Config, sess db.Session, tx TorrentClient, diskGuard *downloads.DiskGuard, quotas *downloads.Quotas, handlerOptions ...connect.HandlerOption) *Server {
+	s := &Server{
+		logger:          zap.L(),
+		tx:              tx,
+		sess:            sess,
+		config:          cfg.GRPC,
+		scraperConfig:   cfg.Transmission.Scraper,
+		stalledConfig:   cfg.Transmission.Stalled,
+		seedingPolicies: cfg.Transmission.SeedingPolicies,
+		diskGuard:       diskGuard,
+		quotas:          quotas,
+		lastSampled:     make(map[string]time.Time),
+		notifying:       make(map[string]int),
+		handlerOptions:  handlerOptions,
+		shutdownTimeout: cfg.ShutdownTimeout,
+		routes:          make(map[string]http.Handler),
+		opsConfig:       cfg.Ops,
+		opsRoutes:       make(map[string]http.Handler),
+	}
+	// Scrapes are only late once the first has had time to finish
+	s.lastScraped.Store(time.Now().UnixNano())
+	return s
+}
+
+// Handle serves handler on the RPC server as well, it must be called before Run.
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.routes[pattern] = handler
+}
+
+// LastScrape is when transmission was last scraped successfully, or when the server was created.
+func (s *Server) LastScrape() time.Time {
+	return time.Unix(0, s.lastScraped.Load())
+}
+
+// process runs the per torrent policies on a scraped torrent.
+func (s *Server) process(ctx context.Context, torrent *models.Torrent, files []transmission.File, now time.Time) error {
+	if err := s.detectCategory(ctx, torrent, files); err != nil {
+		return err
+	}
+	if err := s.guardDiskSpace(ctx, torrent); err != nil {
+		return err
+	}
+	if err := s.enforceQuota(ctx, torrent); err != nil {
+		return err
+	}
+	sampled, err := s.sample(ctx, torrent, now)
+	if err != nil {
+		return err
+	}
+	if !sampled {
+		return nil
+	}
+	if err := s.checkStalled(ctx, torrent, now); err != nil {
+		return err
+	}
+	return s.applySeedingPolicy(ctx, torrent, now)
+}
+
+// SubscribeCompletedTorrents sends torrents to every subscribed channel when they complete,
+// each channel is sent to independently so one slow subscriber doesn't delay the rest.
 func (s *Server) SubscribeCompletedTorrents(c chan<- *models.Torrent) {
-	s.completedTorrents = c
+	s.completedTorrents = append(s.completedTorrents, newSubscriber(c, s.delivered))
 }
 
-var errUnimplemented = errors.New("method is not implemented")
+// notify queues a completed torrent for every subscriber, it is only marked notified once all of them received it
+// so that a completion cut off by shutdown is notified again on startup.
+func (s *Server) notify(ctx context.Context, torrent *models.Torrent) error {
+	if len(s.completedTorrents) == 0 {
+		if err := torrent.MarkNotified(ctx, s.sess); err != nil {
+			return fmt.Errorf("failed marking torrent notified: %w", err)
+		}
+		return nil
+	}
+	s.notifyLock.Lock()
+	s.notifying[torrent.ID] += len(s.completedTorrents)
+	s.notifyLock.Unlock()
+	for _, sub := range s.completedTorrents {
+		sub.push(torrent)
+	}
+	return nil
+}
 
-func (s *Server) DeleteDownload(context.Context, *connect.Request[downloadsv1.DeleteDownloadRequest]) (*connect.Response[downloadsv1.DeleteDownloadResponse], error) {
-	return nil, connect.NewError(connect.CodeUnimplemented, errUnimplemented)
+func (s *Server) delivered(torrent *models.Torrent) {
+	s.notifyLock.Lock()
+	s.notifying[torrent.ID]--
+	remaining := s.notifying[torrent.ID]
+	if remaining <= 0 {
+		delete(s.notifying, torrent.ID)
+	}
+	s.notifyLock.Unlock()
+	if remaining > 0 {
+		return
+	}
+	// The subscriber may be stopping, which shouldn't notify the torrent again
+	if err := torrent.MarkNotified(context.Background(), s.sess); err != nil {
+		s.logger.Error("failed to mark torrent notified", zap.Error(err), zap.String("name", torrent.NameString()))
+	}
 }
 
-func (s *Server) GetDownloads(context.Context, *connect.Request[downloadsv1.GetDownloadsRequest]) (*connect.Response[downloadsv1.GetDownloadsResponse], error) {
+// renotify queues the completed torrents which weren't delivered to every subscriber before Polly last stopped.
+func (s *Server) renotify(ctx context.Context) error {
+	torrents, err := models.GetUnnotifiedTorrents(ctx, s.sess)
+	if err != nil {
+		return fmt.Errorf("failed getting unnotified torrents: %w", err)
+	}
+	for _, torrent := range torrents {
+		if err := s.notify(ctx, torrent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SubscribeStalledTorrents sends torrents when they are flagged as stalled,
+// and again with DeletedAt set if they are removed for staying stalled.
+// Like completed torrents they're queued, so a slow subscriber doesn't block the scraper.
+func (s *Server) SubscribeStalledTorrents(c chan<- *models.Torrent) {
+	s.stalledTorrents = newSubscriber(c, func(*models.Torrent) {})
+}
+
+// ProcedureScopes are the API key scopes each RPC requires.
+var ProcedureScopes = map[string]string{
+	downloadsv1connect.DownloadServiceGetDownloadsProcedure:   models.ScopeRead,
+	downloadsv1connect.DownloadServiceDeleteDownloadProcedure: models.ScopeWrite,
+	// Reflection only describes the services, so anyone who can read can use it
+	"/grpc.reflection.v1.ServerReflection/ServerReflectionInfo":      models.ScopeRead,
+	"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo": models.ScopeRead,
+}
+
+var (
+	errUnimplemented   = errors.New("method is not implemented")
+	errInvalidClientCA = errors.New("client CA file contains no certificates")
+)
+
+func (s *Server) DeleteDownload(context.Context, *connect.Request[downloadsv1.DeleteDownloadRequest]) (*connect.Response[downloadsv1.DeleteDownloadResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errUnimplemented)
 }
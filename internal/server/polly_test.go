@@ -0,0 +1,25 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func Test_drainContext(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	drainCtx, done := drainContext(ctx, 50*time.Millisecond)
+	defer done()
+	cancel()
+	select {
+	case <-drainCtx.Done():
+		t.Fatal("drainContext() was cancelled with its parent")
+	case <-time.After(10 * time.Millisecond):
+	}
+	select {
+	case <-drainCtx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("drainContext() wasn't cancelled after the grace period")
+	}
+}
@@ -0,0 +1,57 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bobcob7/polly-bot/internal/downloads"
+	"github.com/bobcob7/polly-bot/internal/models"
+	"go.uber.org/zap"
+)
+
+// enforceQuota checks torrents which were added without a known size against their requester's quota,
+// once their size is known. Torrents which exceed it are removed.
+func (s *Server) enforceQuota(ctx context.Context, torrent *models.Torrent) error {
+	if s.quotas == nil || torrent.TotalSize == 0 || torrent.DeletedAt != nil {
+		return nil
+	}
+	value, ok := torrent.Label(models.QuotaLabel)
+	if !ok || !strings.HasPrefix(value, downloads.QuotaPending) {
+		return nil
+	}
+	var roles []string
+	if rawRoles := strings.TrimPrefix(value, downloads.QuotaPending); rawRoles != "" {
+		roles = strings.Split(rawRoles, ",")
+	}
+	checkErr := s.quotas.Recheck(ctx, torrent.RequestedBy, roles)
+	if checkErr == nil {
+		if err := torrent.SetLabel(ctx, s.sess, models.QuotaLabel, downloads.QuotaOK); err != nil {
+			return fmt.Errorf("failed setting quota label: %w", err)
+		}
+		return nil
+	}
+	if !downloads.IsQuotaExceeded(checkErr) {
+		return fmt.Errorf("failed checking quota: %w", checkErr)
+	}
+	id, err := strconv.Atoi(torrent.ID)
+	if err != nil {
+		return fmt.Errorf("invalid torrent id %q: %w", torrent.ID, err)
+	}
+	s.logger.Warn("removing torrent over quota",
+		zap.String("name", torrent.NameString()),
+		zap.String("requestedBy", torrent.RequestedBy),
+		zap.Error(checkErr),
+	)
+	if err := s.tx.RemoveTorrents(ctx, true, id); err != nil {
+		return fmt.Errorf("failed removing torrent: %w", err)
+	}
+	if err := torrent.MarkDeleted(ctx, s.sess); err != nil {
+		return fmt.Errorf("failed marking torrent deleted: %w", err)
+	}
+	if err := torrent.SetLabel(ctx, s.sess, models.QuotaLabel, downloads.QuotaRejected+checkErr.Error()); err != nil {
+		return fmt.Errorf("failed setting quota label: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bobcob7/polly-bot/internal/config"
+	"github.com/bobcob7/polly-bot/internal/downloads"
+	"github.com/bobcob7/polly-bot/internal/models"
+	"github.com/bobcob7/transmission-rpc"
+)
+
+func TestServer_scrapeQuota(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		size        uint64
+		wantRemoved bool
+		wantLabel   string
+	}{
+		"Within quota": {
+			size:      50,
+			wantLabel: downloads.QuotaOK,
+		},
+		"Over quota": {
+			size:        500,
+			wantRemoved: true,
+			wantLabel:   downloads.QuotaRejected,
+		},
+	}
+	for name, tt := range tests {
+		testData := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			sess := testSession(t)
+			//nolint: gosec
+			id := rand.Intn(1 << 30)
+			requestedBy := "user-" + strconv.Itoa(id)
+			// Added from a magnet link without a size
+			added := &models.Torrent{
+				ID:         strconv.Itoa(id),
+				Name:       "Magnet",
+				CreatedAt:  time.Now(),
+				Status:     4,
+				MagnetLink: "magnet:?xt=urn:btih:" + strconv.Itoa(id),
+				TorrentMetadata: &models.TorrentMetadata{
+					RequestedBy: requestedBy,
+					Labels:      map[string]string{models.QuotaLabel: downloads.QuotaPending},
+				},
+			}
+			if _, err := added.Set(context.Background(), sess); err != nil {
+				t.Fatal(err)
+			}
+			tx := &fakeTorrentClient{
+				torrents: []transmission.Torrent{{
+					ID:           id,
+					Name:         "Magnet",
+					AddedDate:    uint64(time.Now().Unix()),
+					Status:       4,
+					MagnetLink:   added.MagnetLink,
+					SizeWhenDone: testData.size,
+				}},
+			}
+			quotas := downloads.NewQuotas(config.Quotas{Default: config.Quota{DailyBytes: 100}}, config.Access{}, sess)
+			s := New(config.New(), sess, tx, nil, quotas)
+			if err := s.scrape(context.Background()); err != nil {
+				t.Fatalf("Server.scrape() error = %v", err)
+			}
+			var wantRemoved []int
+			if testData.wantRemoved {
+				wantRemoved = []int{id}
+			}
+			if !reflect.DeepEqual(tx.removed, wantRemoved) {
+				t.Errorf("Server.scrape() removed %v, want %v", tx.removed, wantRemoved)
+			}
+			torrent := &models.Torrent{ID: strconv.Itoa(id)}
+			if err := torrent.Get(context.Background(), sess); err != nil {
+				t.Fatal(err)
+			}
+			if label, _ := torrent.Label(models.QuotaLabel); !strings.HasPrefix(label, testData.wantLabel) {
+				t.Errorf("Server.scrape() labelled %q, want %q", label, testData.wantLabel)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bobcob7/polly-bot/internal/config"
+	"github.com/bobcob7/polly-bot/internal/models"
+	"go.uber.org/zap"
+)
+
+// seedingPolicy returns the policy for the first of the torrent's categories with one,
+// falling back to the policy without a category.
+func seedingPolicy(policies []config.SeedingPolicy, torrent *models.Torrent) (config.SeedingPolicy, bool) {
+	var categories []string
+	if torrent.TorrentMetadata != nil {
+		categories = torrent.Categories
+	}
+	for _, category := range categories {
+		for _, policy := range policies {
+			if policy.Category != "" && strings.EqualFold(policy.Category, category) {
+				return policy, true
+			}
+		}
+	}
+	for _, policy := range policies {
+		if policy.Category == "" {
+			return policy, true
+		}
+	}
+	return config.SeedingPolicy{}, false
+}
+
+// seedingSatisfied returns the reason a completed torrent has seeded enough under the policy,
+// or false if it should keep seeding.
+func seedingSatisfied(policy config.SeedingPolicy, torrent *models.Torrent, now time.Time) (string, bool) {
+	if torrent.CompletedAt == nil {
+		return "", false
+	}
+	seedTime := now.Sub(*torrent.CompletedAt)
+	if policy.MaxSeedTime != 0 && seedTime >= policy.MaxSeedTime {
+		return fmt.Sprintf("seeded for the maximum of %s", policy.MaxSeedTime), true
+	}
+	if seedTime < policy.MinSeedTime {
+		return "", false
+	}
+	if policy.Ratio == 0 {
+		return fmt.Sprintf("seeded for the minimum of %s", policy.MinSeedTime), true
+	}
+	ratio := torrent.Ratio()
+	if ratio >= policy.Ratio {
+		return fmt.Sprintf("reached ratio %.2f of %.2f", ratio, policy.Ratio), true
+	}
+	return "", false
+}
+
+// applySeedingPolicy stops or removes completed torrents which satisfied their seeding policy.
+func (s *Server) applySeedingPolicy(ctx context.Context, torrent *models.Torrent, now time.Time) error {
+	if _, applied := torrent.Label(models.SeedingPolicyLabel); applied {
+		return nil
+	}
+	policy, ok := seedingPolicy(s.seedingPolicies, torrent)
+	if !ok {
+		return nil
+	}
+	reason, done := seedingSatisfied(policy, torrent, now)
+	if !done {
+		return nil
+	}
+	id, err := strconv.Atoi(torrent.ID)
+	if err != nil {
+		return fmt.Errorf("invalid torrent id %q: %w", torrent.ID, err)
+	}
+	s.logger.Info("seeding policy satisfied",
+		zap.String("name", torrent.NameString()),
+		zap.String("action", policy.Action),
+		zap.String("reason", reason),
+	)
+	switch policy.Action {
+	case config.SeedingActionStop:
+		if err := s.tx.StopTorrents(ctx, id); err != nil {
+			return fmt.Errorf("failed stopping torrent: %w", err)
+		}
+		reason = "stopped: " + reason
+	case config.SeedingActionRemove:
+		if err := s.tx.RemoveTorrents(ctx, false, id); err != nil {
+			return fmt.Errorf("failed removing torrent: %w", err)
+		}
+		if err := torrent.MarkDeleted(ctx, s.sess); err != nil {
+			return fmt.Errorf("failed marking torrent deleted: %w", err)
+		}
+		reason = "removed: " + reason
+	}
+	if err := torrent.SetLabel(ctx, s.sess, models.SeedingPolicyLabel, reason); err != nil {
+		return fmt.Errorf("failed setting seeding policy label: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bobcob7/polly-bot/internal/config"
+	"github.com/bobcob7/polly-bot/internal/models"
+)
+
+func Test_seedingPolicy(t *testing.T) {
+	t.Parallel()
+	policies := []config.SeedingPolicy{
+		{Category: "MOVIE", Ratio: 2, Action: config.SeedingActionStop},
+		{Ratio: 1, Action: config.SeedingActionRemove},
+	}
+	tests := map[string]struct {
+		torrent *models.Torrent
+		want    float64
+	}{
+		"No metadata": {
+			torrent: &models.Torrent{},
+			want:    1,
+		},
+		"Matching category": {
+			torrent: &models.Torrent{TorrentMetadata: &models.TorrentMetadata{Categories: []string{"movie"}}},
+			want:    2,
+		},
+		"Other category": {
+			torrent: &models.Torrent{TorrentMetadata: &models.TorrentMetadata{Categories: []string{"MUSIC"}}},
+			want:    1,
+		},
+	}
+	for name, tt := range tests {
+		testData := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, ok := seedingPolicy(policies, testData.torrent)
+			if !ok {
+				t.Fatal("seedingPolicy() found no policy")
+			}
+			if got.Ratio != testData.want {
+				t.Errorf("seedingPolicy() ratio = %v, want %v", got.Ratio, testData.want)
+			}
+		})
+	}
+}
+
+func Test_seedingSatisfied(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+	completed := func(ago time.Duration) *time.Time {
+		at := now.Add(-ago)
+		return &at
+	}
+	tests := map[string]struct {
+		policy  config.SeedingPolicy
+		torrent models.Torrent
+		want    bool
+	}{
+		"Not completed": {
+			policy:  config.SeedingPolicy{Ratio: 1},
+			torrent: models.Torrent{TotalSize: 10, Uploaded: 100},
+			want:    false,
+		},
+		"Ratio reached": {
+			policy:  config.SeedingPolicy{Ratio: 2},
+			torrent: models.Torrent{TotalSize: 10, Uploaded: 20, CompletedAt: completed(time.Hour)},
+			want:    true,
+		},
+		"Ratio not reached": {
+			policy:  config.SeedingPolicy{Ratio: 2},
+			torrent: models.Torrent{TotalSize: 10, Uploaded: 19, CompletedAt: completed(time.Hour)},
+			want:    false,
+		},
+		"Ratio reached before minimum seed time": {
+			policy:  config.SeedingPolicy{Ratio: 2, MinSeedTime: 2 * time.Hour},
+			torrent: models.Torrent{TotalSize: 10, Uploaded: 20, CompletedAt: completed(time.Hour)},
+			want:    false,
+		},
+		"Minimum seed time without ratio": {
+			policy:  config.SeedingPolicy{MinSeedTime: time.Hour},
+			torrent: models.Torrent{TotalSize: 10, CompletedAt: completed(time.Hour)},
+			want:    true,
+		},
+		"Maximum seed time": {
+			policy:  config.SeedingPolicy{Ratio: 2, MaxSeedTime: time.Hour},
+			torrent: models.Torrent{TotalSize: 10, CompletedAt: completed(time.Hour)},
+			want:    true,
+		},
+	}
+	for name, tt := range tests {
+		testData := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			reason, got := seedingSatisfied(testData.policy, &testData.torrent, now)
+			if got != testData.want {
+				t.Errorf("seedingSatisfied() = %v (%q), want %v", got, reason, testData.want)
+			}
+		})
+	}
+}
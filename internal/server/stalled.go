@@ -0,0 +1,82 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/bobcob7/polly-bot/internal/models"
+	"go.uber.org/zap"
+)
+
+const statusStopped = 0
+
+// checkStalled flags torrents which made no progress within the stall window and removes
+// torrents which stayed stalled for longer than the configured deadline.
+func (s *Server) checkStalled(ctx context.Context, torrent *models.Torrent, now time.Time) error {
+	stalledSince, stalled := torrent.Label(models.StalledLabel)
+	if torrent.CompletedAt != nil || torrent.Status == statusStopped {
+		if stalled {
+			if err := torrent.DeleteLabel(ctx, s.sess, models.StalledLabel); err != nil {
+				return fmt.Errorf("failed clearing stalled label: %w", err)
+			}
+		}
+		return nil
+	}
+	if rawKeepUntil, ok := torrent.Label(models.StalledKeepUntilLabel); ok {
+		keepUntil, err := time.Parse(time.RFC3339, rawKeepUntil)
+		if err == nil && now.Before(keepUntil) {
+			return nil
+		}
+	}
+	samples, err := models.GetTorrentSamples(ctx, s.sess, torrent.ID, now.Add(-s.stalledConfig.Window))
+	if err != nil {
+		return fmt.Errorf("failed getting samples: %w", err)
+	}
+	// Only judge torrents which have been sampled for the whole window
+	if len(samples) < 2 || samples[0].SampledAt.After(now.Add(-s.stalledConfig.Window+s.scraperConfig.SampleResolution)) {
+		return nil
+	}
+	if samples[len(samples)-1].Downloaded > samples[0].Downloaded {
+		if stalled {
+			s.logger.Info("torrent is no longer stalled", zap.String("name", torrent.NameString()))
+			if err := torrent.DeleteLabel(ctx, s.sess, models.StalledLabel); err != nil {
+				return fmt.Errorf("failed clearing stalled label: %w", err)
+			}
+		}
+		return nil
+	}
+	if !stalled {
+		s.logger.Info("torrent is stalled", zap.String("name", torrent.NameString()))
+		if err := torrent.SetLabel(ctx, s.sess, models.StalledLabel, now.UTC().Format(time.RFC3339)); err != nil {
+			return fmt.Errorf("failed setting stalled label: %w", err)
+		}
+		if s.stalledTorrents != nil {
+			s.stalledTorrents.push(torrent)
+		}
+		return nil
+	}
+	if s.stalledConfig.RemoveAfter == 0 {
+		return nil
+	}
+	since, err := time.Parse(time.RFC3339, stalledSince)
+	if err != nil || now.Sub(since) < s.stalledConfig.RemoveAfter {
+		return nil
+	}
+	s.logger.Info("removing stalled torrent", zap.String("name", torrent.NameString()))
+	id, err := strconv.Atoi(torrent.ID)
+	if err != nil {
+		return fmt.Errorf("invalid torrent id %q: %w", torrent.ID, err)
+	}
+	if err := s.tx.RemoveTorrents(ctx, true, id); err != nil {
+		return fmt.Errorf("failed removing stalled torrent from transmission: %w", err)
+	}
+	if err := torrent.MarkDeleted(ctx, s.sess); err != nil {
+		return fmt.Errorf("failed marking stalled torrent deleted: %w", err)
+	}
+	if s.stalledTorrents != nil {
+		s.stalledTorrents.push(torrent)
+	}
+	return nil
+}
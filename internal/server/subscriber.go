@@ -0,0 +1,70 @@
+package server
+
+import (
+	"context"
+	"sync"
+
+	"github.com/bobcob7/polly-bot/internal/models"
+)
+
+// subscriber queues torrents for a channel, so a slow subscriber can't hold up
+// the scraper or the other subscribers.
+type subscriber struct {
+	c       chan<- *models.Torrent
+	pending chan struct{}
+	// delivered is called with each torrent once the channel received it.
+	delivered func(*models.Torrent)
+
+	lock  sync.Mutex
+	queue []*models.Torrent
+}
+
+func newSubscriber(c chan<- *models.Torrent, delivered func(*models.Torrent)) *subscriber {
+	return &subscriber{
+		c:         c,
+		pending:   make(chan struct{}, 1),
+		delivered: delivered,
+	}
+}
+
+// push queues the torrent without blocking.
+func (s *subscriber) push(torrent *models.Torrent) {
+	s.lock.Lock()
+	s.queue = append(s.queue, torrent)
+	s.lock.Unlock()
+	select {
+	case s.pending <- struct{}{}:
+	default:
+	}
+}
+
+func (s *subscriber) pop() *models.Torrent {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if len(s.queue) == 0 {
+		return nil
+	}
+	torrent := s.queue[0]
+	s.queue[0] = nil
+	s.queue = s.queue[1:]
+	return torrent
+}
+
+// run sends queued torrents to the channel until ctx is done.
+func (s *subscriber) run(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-s.pending:
+		}
+		for torrent := s.pop(); torrent != nil; torrent = s.pop() {
+			select {
+			case s.c <- torrent:
+				s.delivered(torrent)
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package server
+
+import (
+	"context"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/bobcob7/polly-bot/internal/config"
+	"github.com/bobcob7/polly-bot/internal/models"
+)
+
+func Test_subscriber(t *testing.T) {
+	t.Parallel()
+	c := make(chan *models.Torrent)
+	delivered := make(chan string, 3)
+	sub := newSubscriber(c, func(torrent *models.Torrent) { delivered <- torrent.ID })
+	// Pushing mustn't block while nothing is receiving
+	want := []string{"1", "2", "3"}
+	for _, id := range want {
+		sub.push(&models.Torrent{ID: id})
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- sub.run(ctx) }()
+	for _, id := range want {
+		select {
+		case torrent := <-c:
+			if torrent.ID != id {
+				t.Errorf("subscriber.run() sent %v, want %v", torrent.ID, id)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("subscriber.run() didn't send %v", id)
+		}
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("subscriber.run() error = %v", err)
+	}
+	close(delivered)
+	got := make([]string, 0, len(want))
+	for id := range delivered {
+		got = append(got, id)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("subscriber.run() delivered %v, want %v", got, want)
+	}
+}
+
+func TestServer_renotify(t *testing.T) {
+	t.Parallel()
+	sess := testSession(t)
+	//nolint: gosec
+	id := strconv.Itoa(rand.Intn(1 << 30))
+	// Completed before Polly stopped, without reaching the subscribers
+	completedAt := time.Now()
+	completed := &models.Torrent{
+		ID:          id,
+		Name:        "Completed",
+		CreatedAt:   time.Now(),
+		CompletedAt: &completedAt,
+	}
+	if _, err := completed.Set(context.Background(), sess); err != nil {
+		t.Fatal(err)
+	}
+	s := New(config.New(), sess, &fakeTorrentClient{}, nil, nil)
+	c := make(chan *models.Torrent)
+	s.SubscribeCompletedTorrents(c)
+	if err := s.renotify(context.Background()); err != nil {
+		t.Fatalf("Server.renotify() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = s.completedTorrents[0].run(ctx) }()
+	timeout := time.After(time.Second)
+	for received := false; !received; {
+		select {
+		case torrent := <-c:
+			received = torrent.ID == id
+		case <-timeout:
+			t.Fatalf("Server.renotify() didn't send %v", id)
+		}
+	}
+	for {
+		unnotified, err := models.GetUnnotifiedTorrents(context.Background(), sess)
+		if err != nil {
+			t.Fatal(err)
+		}
+		found := false
+		for _, torrent := range unnotified {
+			found = found || torrent.ID == id
+		}
+		if !found {
+			return
+		}
+		select {
+		case <-timeout:
+			t.Fatalf("Server.renotify() didn't mark %v notified", id)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
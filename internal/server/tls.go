@@ -0,0 +1,125 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// certificateReloader serves a certificate and key from disk, reloading them when either file changes,
+// so renewed certificates are picked up without a restart.
+type certificateReloader struct {
+	logger   *zap.Logger
+	certFile string
+	keyFile  string
+	period   time.Duration
+	now      func() time.Time
+
+	lock        sync.Mutex
+	certificate *tls.Certificate
+	modified    time.Time
+	checked     time.Time
+}
+
+func newCertificateReloader(certFile, keyFile string, period time.Duration) (*certificateReloader, error) {
+	c := &certificateReloader{
+		logger:   zap.L().With(zap.String("component", "tls")),
+		certFile: certFile,
+		keyFile:  keyFile,
+		period:   period,
+		now:      time.Now,
+	}
+	modified, err := c.lastModified()
+	if err != nil {
+		return nil, err
+	}
+	if err := c.load(modified); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// lastModified is the latest modification time of the certificate and key files.
+func (c *certificateReloader) lastModified() (time.Time, error) {
+	var modified time.Time
+	for _, file := range []string{c.certFile, c.keyFile} {
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed checking certificate: %w", err)
+		}
+		if info.ModTime().After(modified) {
+			modified = info.ModTime()
+		}
+	}
+	return modified, nil
+}
+
+func (c *certificateReloader) load(modified time.Time) error {
+	certificate, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed loading certificate: %w", err)
+	}
+	c.certificate = &certificate
+	c.modified = modified
+	c.checked = c.now()
+	return nil
+}
+
+// GetCertificate checks the files at most once per period, a certificate which fails to
+// reload is logged and the previous one is kept.
+func (c *certificateReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.now().Sub(c.checked) < c.period {
+		return c.certificate, nil
+	}
+	c.checked = c.now()
+	modified, err := c.lastModified()
+	if err != nil {
+		c.logger.Error("failed to check certificate", zap.Error(err))
+		return c.certificate, nil
+	}
+	if !modified.After(c.modified) {
+		return c.certificate, nil
+	}
+	if err := c.load(modified); err != nil {
+		c.logger.Error("failed to reload certificate", zap.Error(err))
+		return c.certificate, nil
+	}
+	c.logger.Info("reloaded certificate", zap.Time("modified", modified))
+	return c.certificate, nil
+}
+
+// tlsConfig returns nil when RPCs are served in cleartext.
+func (s *Server) tlsConfig() (*tls.Config, error) {
+	if s.config.CertFile == "" {
+		return nil, nil
+	}
+	reloader, err := newCertificateReloader(s.config.CertFile, s.config.KeyFile, s.config.ReloadPeriod)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: reloader.GetCertificate,
+	}
+	if s.config.ClientCAFile != "" {
+		pem, err := os.ReadFile(s.config.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed reading client CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errInvalidClientCA
+		}
+		// Callers without a certificate can still use an API key
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+		tlsConfig.ClientCAs = pool
+	}
+	return tlsConfig, nil
+}
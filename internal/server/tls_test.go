@@ -0,0 +1,116 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeCertificate(t *testing.T, certFile, keyFile, name string, modified time.Time) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range []string{certFile, keyFile} {
+		if err := os.Chtimes(file, modified, modified); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func Test_certificateReloader(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		elapsed time.Duration
+		replace bool
+		corrupt bool
+		want    string
+	}{
+		"Unchanged": {
+			elapsed: 2 * time.Minute,
+			want:    "first",
+		},
+		"Replaced": {
+			elapsed: 2 * time.Minute,
+			replace: true,
+			want:    "second",
+		},
+		"Replaced within period": {
+			elapsed: 30 * time.Second,
+			replace: true,
+			want:    "first",
+		},
+		"Invalid replacement": {
+			elapsed: 2 * time.Minute,
+			corrupt: true,
+			want:    "first",
+		},
+	}
+	for name, tt := range tests {
+		testData := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			certFile := filepath.Join(dir, "tls.crt")
+			keyFile := filepath.Join(dir, "tls.key")
+			start := time.Now().Add(-time.Hour)
+			writeCertificate(t, certFile, keyFile, "first", start)
+			reloader, err := newCertificateReloader(certFile, keyFile, time.Minute)
+			if err != nil {
+				t.Fatalf("newCertificateReloader() error = %v", err)
+			}
+			now := time.Now()
+			reloader.now = func() time.Time { return now }
+			reloader.checked = now
+			if testData.replace {
+				writeCertificate(t, certFile, keyFile, "second", start.Add(time.Minute))
+			}
+			if testData.corrupt {
+				if err := os.WriteFile(certFile, []byte("invalid"), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			now = now.Add(testData.elapsed)
+			certificate, err := reloader.GetCertificate(nil)
+			if err != nil {
+				t.Fatalf("certificateReloader.GetCertificate() error = %v", err)
+			}
+			leaf, err := x509.ParseCertificate(certificate.Certificate[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if leaf.Subject.CommonName != testData.want {
+				t.Errorf("certificateReloader.GetCertificate() = %q, want %q", leaf.Subject.CommonName, testData.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package torrent
+
+import (
+	"bytes"
+	"crypto/sha1" //nolint: gosec
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	errInvalidBencode = errors.New("invalid bencode")
+	errMissingInfo    = errors.New("missing 'info' dictionary")
+	errMissingName    = errors.New("missing 'name' in info dictionary")
+	errBencodeTooDeep = errors.New("bencode nested too deeply")
+)
+
+// maxBencodeDepth limits nesting, so a crafted file can't exhaust the stack.
+// Real .torrent files nest a handful of levels.
+const maxBencodeDepth = 32
+
+// Metainfo is the subset of a .torrent file Polly reads, the file itself is added to transmission.
+type Metainfo struct {
+	InfoHash string
+	Name     string
+	// Length is the total size of all files in bytes.
+	Length   uint64
+	Files    []File
+	Trackers []string
+}
+
+// File is a file within a torrent, with a slash separated path relative to the torrent's name.
+type File struct {
+	Path   string
+	Length uint64
+}
+
+// ParseMetainfo decodes the contents of a .torrent file.
+func ParseMetainfo(data []byte) (*Metainfo, error) {
+	d := bencodeDecoder{data: data}
+	root, err := d.decode()
+	if err != nil {
+		return nil, err
+	}
+	dict, ok := root.(map[string]interface{})
+	if !ok || d.infoEnd == 0 {
+		return nil, errMissingInfo
+	}
+	info, ok := dict["info"].(map[string]interface{})
+	if !ok {
+		return nil, errMissingInfo
+	}
+	//nolint: gosec
+	hash := sha1.Sum(data[d.infoStart:d.infoEnd])
+	m := &Metainfo{
+		InfoHash: hex.EncodeToString(hash[:]),
+	}
+	name, ok := info["name"].([]byte)
+	if !ok {
+		return nil, errMissingName
+	}
+	m.Name = string(name)
+	if length, ok := info["length"].(int64); ok && length > 0 {
+		// Single file torrents are named after their only file
+		m.Length = uint64(length)
+		m.Files = []File{{Path: m.Name, Length: m.Length}}
+	}
+	if files, ok := info["files"].([]interface{}); ok {
+		for _, rawFile := range files {
+			file, ok := rawFile.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			length, _ := file["length"].(int64)
+			if length < 0 {
+				length = 0
+			}
+			rawPath, _ := file["path"].([]interface{})
+			parts := make([]string, 0, len(rawPath))
+			for _, rawPart := range rawPath {
+				if part, ok := rawPart.([]byte); ok {
+					parts = append(parts, string(part))
+				}
+			}
+			m.Length += uint64(length)
+			m.Files = append(m.Files, File{Path: strings.Join(parts, "/"), Length: uint64(length)})
+		}
+	}
+	if announce, ok := dict["announce"].([]byte); ok {
+		m.Trackers = append(m.Trackers, string(announce))
+	}
+	if tiers, ok := dict["announce-list"].([]interface{}); ok {
+		for _, rawTier := range tiers {
+			tier, _ := rawTier.([]interface{})
+			for _, rawTracker := range tier {
+				if tracker, ok := rawTracker.([]byte); ok && !contains(m.Trackers, string(tracker)) {
+					m.Trackers = append(m.Trackers, string(tracker))
+				}
+			}
+		}
+	}
+	return m, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// bencodeDecoder decodes bencoded data, remembering where the top level info dictionary is
+// so that its hash can be calculated from the original bytes.
+type bencodeDecoder struct {
+	data      []byte
+	pos       int
+	depth     int
+	infoStart int
+	infoEnd   int
+}
+
+func (d *bencodeDecoder) decode() (interface{}, error) {
+	if d.pos >= len(d.data) {
+		return nil, errInvalidBencode
+	}
+	switch c := d.data[d.pos]; {
+	case c == 'i':
+		end := bytes.IndexByte(d.data[d.pos:], 'e')
+		if end < 0 {
+			return nil, errInvalidBencode
+		}
+		value, err := strconv.ParseInt(string(d.data[d.pos+1:d.pos+end]), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bencode integer: %w", err)
+		}
+		d.pos += end + 1
+		return value, nil
+	case c == 'l':
+		d.pos++
+		d.depth++
+		if d.depth > maxBencodeDepth {
+			return nil, errBencodeTooDeep
+		}
+		list := make([]interface{}, 0)
+		for d.pos < len(d.data) && d.data[d.pos] != 'e' {
+			value, err := d.decode()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		if d.pos >= len(d.data) {
+			return nil, errInvalidBencode
+		}
+		d.pos++
+		d.depth--
+		return list, nil
+	case c == 'd':
+		d.pos++
+		d.depth++
+		if d.depth > maxBencodeDepth {
+			return nil, errBencodeTooDeep
+		}
+		dict := make(map[string]interface{})
+		for d.pos < len(d.data) && d.data[d.pos] != 'e' {
+			key, err := d.decodeString()
+			if err != nil {
+				return nil, err
+			}
+			start := d.pos
+			value, err := d.decode()
+			if err != nil {
+				return nil, err
+			}
+			if d.depth == 1 && string(key) == "info" {
+				d.infoStart, d.infoEnd = start, d.pos
+			}
+			dict[string(key)] = value
+		}
+		if d.pos >= len(d.data) {
+			return nil, errInvalidBencode
+		}
+		d.pos++
+		d.depth--
+		return dict, nil
+	case c >= '0' && c <= '9':
+		return d.decodeString()
+	default:
+		return nil, errInvalidBencode
+	}
+}
+
+func (d *bencodeDecoder) decodeString() ([]byte, error) {
+	colon := bytes.IndexByte(d.data[d.pos:], ':')
+	if colon < 0 {
+		return nil, errInvalidBencode
+	}
+	length, err := strconv.Atoi(string(d.data[d.pos : d.pos+colon]))
+	if err != nil || length < 0 {
+		return nil, errInvalidBencode
+	}
+	start := d.pos + colon + 1
+	// Compared without adding, since a huge length would overflow
+	if length > len(d.data)-start {
+		return nil, errInvalidBencode
+	}
+	d.pos = start + length
+	return d.data[start:d.pos], nil
+}
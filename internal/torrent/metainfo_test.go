@@ -0,0 +1,67 @@
+package torrent
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseMetainfo(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		data    string
+		want    *Metainfo
+		wantErr bool
+	}{
+		"Single file": {
+			data: "d8:announce13:http://a/anno4:infod6:lengthi42e4:name8:file.iso12:piece lengthi16384e6:pieces0:ee",
+			want: &Metainfo{
+				InfoHash: "b76e24c95d6b80f1fd56d713aa1dd91c29bfd32e",
+				Name:     "file.iso",
+				Length:   42,
+				Files:    []File{{Path: "file.iso", Length: 42}},
+				Trackers: []string{"http://a/anno"},
+			},
+		},
+		"Multiple files": {
+			data: "d13:announce-listll13:http://a/annoel13:http://b/annoee4:infod5:filesld6:lengthi100e4:pathl5:a.mkveed6:lengthi23e4:pathl5:b.nfoeee4:name4:Show12:piece lengthi16384e6:pieces0:ee",
+			want: &Metainfo{
+				InfoHash: "85ef4a0a795174dd33336329b20cd0d59c24554c",
+				Name:     "Show",
+				Length:   123,
+				Files:    []File{{Path: "a.mkv", Length: 100}, {Path: "b.nfo", Length: 23}},
+				Trackers: []string{"http://a/anno", "http://b/anno"},
+			},
+		},
+		"Missing info": {
+			data:    "d8:announce13:http://a/annoe",
+			wantErr: true,
+		},
+		"Truncated": {
+			data:    "d4:infod4:name",
+			wantErr: true,
+		},
+		"Overflowing string length": {
+			data:    "d9223372036854775807:ae",
+			wantErr: true,
+		},
+		"Nested too deeply": {
+			data:    "d4:infod4:name1:a5:extra" + strings.Repeat("l", 100000),
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		testData := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseMetainfo([]byte(testData.data))
+			if (err != nil) != testData.wantErr {
+				t.Errorf("ParseMetainfo() error = %v, wantErr %v", err, testData.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, testData.want) {
+				t.Errorf("ParseMetainfo() = %+v, want %+v", got, testData.want)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"strconv"
 )
 
 var errMissingDNQuery = errors.New("missing 'dn' query parameter")
@@ -31,3 +32,16 @@ func MagnetURIDisplayName(uri string) (string, error) {
 	}
 	return displayName, nil
 }
+
+// MagnetURISize returns the exact length from the magnet link's 'xl' parameter, or false if it isn't set.
+func MagnetURISize(uri string) (uint64, bool) {
+	parsedURI, err := url.Parse(uri)
+	if err != nil || parsedURI.Scheme != "magnet" {
+		return 0, false
+	}
+	size, err := strconv.ParseUint(parsedURI.Query().Get("xl"), 10, 64)
+	if err != nil || size == 0 {
+		return 0, false
+	}
+	return size, true
+}
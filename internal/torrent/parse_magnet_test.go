@@ -44,3 +44,37 @@ func TestParseMagnetURI(t *testing.T) {
 		})
 	}
 }
+
+func TestMagnetURISize(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		uri    string
+		want   uint64
+		wantOk bool
+	}{
+		"Size": {
+			uri:    "magnet:?xt=urn:btih:b76e24c95d6b80f1fd56d713aa1dd91c29bfd32e&dn=file.iso&xl=42",
+			want:   42,
+			wantOk: true,
+		},
+		"Missing size": {
+			uri: "magnet:?xt=urn:btih:b76e24c95d6b80f1fd56d713aa1dd91c29bfd32e&dn=file.iso",
+		},
+		"Invalid size": {
+			uri: "magnet:?xt=urn:btih:b76e24c95d6b80f1fd56d713aa1dd91c29bfd32e&xl=big",
+		},
+		"Incorrect schema": {
+			uri: "http://example.com/?xl=42",
+		},
+	}
+	for name, tt := range tests {
+		testData := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, ok := MagnetURISize(testData.uri)
+			if got != testData.want || ok != testData.wantOk {
+				t.Errorf("MagnetURISize() = %v, %v, want %v, %v", got, ok, testData.want, testData.wantOk)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package torrent
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Labels set from a parsed release name.
+const (
+	TitleLabel      = "title"
+	YearLabel       = "year"
+	SeasonLabel     = "season"
+	EpisodeLabel    = "episode"
+	ResolutionLabel = "resolution"
+	SourceLabel     = "source"
+	CodecLabel      = "codec"
+	GroupLabel      = "group"
+)
+
+// Release is the information encoded in a scene style release name like
+// "Some.Show.S01E02.1080p.WEB-DL.x264-GRP". Unknown fields are left empty.
+type Release struct {
+	Title string
+	Year  int
+	// Season and episodes are zero when not found, LastEpisode equals FirstEpisode for a single episode.
+	Season       int
+	FirstEpisode int
+	LastEpisode  int
+	Resolution   string
+	Source       string
+	Codec        string
+	Group        string
+}
+
+var (
+	releaseSeparatorRegexp = regexp.MustCompile(`[\s._]+`)
+	releaseGroupRegexp     = regexp.MustCompile(`-([A-Za-z0-9]+)(?:\[[^\]]*\])?$`)
+	releaseBracketRegexp   = regexp.MustCompile(`\[[^\]]*\]`)
+	releaseCodecRegexp     = regexp.MustCompile(`(?i)\b([hx])\.(26[45])\b`)
+	releaseExtensionRegexp = regexp.MustCompile(`(?i)\.(mkv|mp4|avi|m4v|wmv|ts|torrent)$`)
+	releaseYearRegexp      = regexp.MustCompile(`^\(?((?:19|20)\d{2})\)?$`)
+	releaseEpisodeRegexp   = regexp.MustCompile(`(?i)^S(\d{1,2})(?:E(\d{1,3})(?:-?E?(\d{1,3}))?)?$`)
+	releaseCrossRegexp     = regexp.MustCompile(`(?i)^(\d{1,2})x(\d{2,3})(?:-(\d{2,3}))?$`)
+	releaseResolution      = regexp.MustCompile(`(?i)^(480|576|720|1080|2160)[pi]$`)
+)
+
+var releaseSources = map[string]string{
+	"web-dl":  "WEB-DL",
+	"webdl":   "WEB-DL",
+	"webrip":  "WEBRip",
+	"web":     "WEB",
+	"bluray":  "BluRay",
+	"blu-ray": "BluRay",
+	"bdrip":   "BluRay",
+	"brrip":   "BluRay",
+	"remux":   "Remux",
+	"hdtv":    "HDTV",
+	"dvdrip":  "DVDRip",
+	"dvd":     "DVD",
+	"hdrip":   "HDRip",
+}
+
+var releaseCodecs = map[string]string{
+	"x264": "x264",
+	"h264": "x264",
+	"avc":  "x264",
+	"x265": "x265",
+	"h265": "x265",
+	"hevc": "x265",
+	"av1":  "AV1",
+	"xvid": "XviD",
+}
+
+// ParseRelease extracts what it can from a release name, everything before the first
+// recognized token becomes the title.
+func ParseRelease(name string) Release {
+	var r Release
+	name = strings.TrimSpace(releaseExtensionRegexp.ReplaceAllString(name, ""))
+	if match := releaseGroupRegexp.FindStringSubmatchIndex(name); match != nil {
+		group := name[match[2]:match[3]]
+		// Avoid mistaking the second half of a source like WEB-DL for a group
+		if _, ok := releaseSources[strings.ToLower(lastToken(name[:match[0]])+"-"+group)]; !ok {
+			r.Group = group
+			name = name[:match[0]]
+		}
+	}
+	name = releaseBracketRegexp.ReplaceAllString(name, " ")
+	name = releaseCodecRegexp.ReplaceAllString(name, "${1}${2}")
+	tokens := releaseSeparatorRegexp.Split(strings.TrimSpace(name), -1)
+	titleEnd := len(tokens)
+	for i, token := range tokens {
+		if r.parseToken(token, i == 0) && i < titleEnd {
+			titleEnd = i
+		}
+	}
+	r.Title = strings.Join(tokens[:titleEnd], " ")
+	return r
+}
+
+// parseToken records a recognized token, returning false if it is part of the title.
+// A year can't be the first token since titles like "1917" are common.
+func (r *Release) parseToken(token string, first bool) bool {
+	lower := strings.ToLower(token)
+	if match := releaseEpisodeRegexp.FindStringSubmatch(token); match != nil && r.Season == 0 {
+		r.Season, _ = strconv.Atoi(match[1])
+		r.setEpisodes(match[2], match[3])
+		return true
+	}
+	if match := releaseCrossRegexp.FindStringSubmatch(token); match != nil && r.Season == 0 {
+		r.Season, _ = strconv.Atoi(match[1])
+		r.setEpisodes(match[2], match[3])
+		return true
+	}
+	if match := releaseYearRegexp.FindStringSubmatch(token); match != nil && !first && r.Year == 0 {
+		r.Year, _ = strconv.Atoi(match[1])
+		return true
+	}
+	if match := releaseResolution.FindStringSubmatch(token); match != nil {
+		r.Resolution = match[1] + "p"
+		return true
+	}
+	if lower == "4k" || lower == "uhd" {
+		r.Resolution = "2160p"
+		return true
+	}
+	if source, ok := releaseSources[lower]; ok {
+		r.Source = source
+		return true
+	}
+	if codec, ok := releaseCodecs[lower]; ok {
+		r.Codec = codec
+		return true
+	}
+	return false
+}
+
+func (r *Release) setEpisodes(first, last string) {
+	r.FirstEpisode, _ = strconv.Atoi(first)
+	r.LastEpisode = r.FirstEpisode
+	if last != "" {
+		r.LastEpisode, _ = strconv.Atoi(last)
+	}
+}
+
+func lastToken(s string) string {
+	tokens := releaseSeparatorRegexp.Split(s, -1)
+	return tokens[len(tokens)-1]
+}
+
+// IsEpisode returns whether the release is an episode or season of a show.
+func (r Release) IsEpisode() bool {
+	return r.Season != 0
+}
+
+// Episode returns the season and episodes like "S01E02" or "S01E02-E04", or "S01" for a whole season.
+func (r Release) Episode() string {
+	switch {
+	case r.Season == 0:
+		return ""
+	case r.FirstEpisode == 0:
+		return fmt.Sprintf("S%02d", r.Season)
+	case r.LastEpisode > r.FirstEpisode:
+		return fmt.Sprintf("S%02dE%02d-E%02d", r.Season, r.FirstEpisode, r.LastEpisode)
+	default:
+		return fmt.Sprintf("S%02dE%02d", r.Season, r.FirstEpisode)
+	}
+}
+
+// FriendlyName returns a readable name like "Some Show S01E02" or "Film (2001)".
+func (r Release) FriendlyName() string {
+	if r.Title == "" {
+		return ""
+	}
+	name := r.Title
+	if r.Year != 0 {
+		name += fmt.Sprintf(" (%d)", r.Year)
+	}
+	if r.IsEpisode() {
+		name += " " + r.Episode()
+	}
+	return name
+}
+
+// Category suggests "TV SHOW" for episodes and "MOVIE" for other video releases.
+func (r Release) Category() string {
+	switch {
+	case r.IsEpisode():
+		return "TV SHOW"
+	case r.Resolution != "" || r.Codec != "" || (r.Year != 0 && r.Source != ""):
+		return "MOVIE"
+	default:
+		return ""
+	}
+}
+
+// Labels returns the found fields keyed by their label names.
+func (r Release) Labels() map[string]string {
+	labels := make(map[string]string)
+	set := func(key, value string) {
+		if value != "" {
+			labels[key] = value
+		}
+	}
+	set(TitleLabel, r.Title)
+	if r.Year != 0 {
+		set(YearLabel, strconv.Itoa(r.Year))
+	}
+	if r.Season != 0 {
+		set(SeasonLabel, fmt.Sprintf("%02d", r.Season))
+	}
+	if r.FirstEpisode != 0 {
+		episode := fmt.Sprintf("%02d", r.FirstEpisode)
+		if r.LastEpisode > r.FirstEpisode {
+			episode += fmt.Sprintf("-%02d", r.LastEpisode)
+		}
+		set(EpisodeLabel, episode)
+	}
+	set(ResolutionLabel, r.Resolution)
+	set(SourceLabel, r.Source)
+	set(CodecLabel, r.Codec)
+	set(GroupLabel, r.Group)
+	return labels
+}
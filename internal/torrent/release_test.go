@@ -0,0 +1,89 @@
+package torrent
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseRelease(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		name string
+		want Release
+	}{
+		"Episode": {
+			name: "Some.Show.S01E02.1080p.WEB-DL.x264-GRP",
+			want: Release{Title: "Some Show", Season: 1, FirstEpisode: 2, LastEpisode: 2, Resolution: "1080p", Source: "WEB-DL", Codec: "x264", Group: "GRP"},
+		},
+		"Episode range": {
+			name: "Some Show S02E03-E05 720p HDTV H.264",
+			want: Release{Title: "Some Show", Season: 2, FirstEpisode: 3, LastEpisode: 5, Resolution: "720p", Source: "HDTV", Codec: "x264"},
+		},
+		"Season pack": {
+			name: "Some.Show.2019.S03.2160p.BluRay.HEVC-GRP[rarbg]",
+			want: Release{Title: "Some Show", Year: 2019, Season: 3, Resolution: "2160p", Source: "BluRay", Codec: "x265", Group: "GRP"},
+		},
+		"Movie": {
+			name: "Steamboy.2004.ANiME.DUAL.iNTERNAL.DVDRip.X264-MULTiPLY",
+			want: Release{Title: "Steamboy", Year: 2004, Source: "DVDRip", Codec: "x264", Group: "MULTiPLY"},
+		},
+		"Year title": {
+			name: "1917.2019.1080p.BluRay.x264.mkv",
+			want: Release{Title: "1917", Year: 2019, Resolution: "1080p", Source: "BluRay", Codec: "x264"},
+		},
+		"Source without group": {
+			name: "Film.2001.WEB-DL",
+			want: Release{Title: "Film", Year: 2001, Source: "WEB-DL"},
+		},
+		"Plain": {
+			name: "Some Album",
+			want: Release{Title: "Some Album"},
+		},
+	}
+	for name, tt := range tests {
+		testData := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if got := ParseRelease(testData.name); !reflect.DeepEqual(got, testData.want) {
+				t.Errorf("ParseRelease() = %+v, want %+v", got, testData.want)
+			}
+		})
+	}
+}
+
+func TestRelease_FriendlyName(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		name         string
+		wantName     string
+		wantCategory string
+	}{
+		"Episode": {
+			name:         "Some.Show.S01E02.1080p.WEB-DL.x264-GRP",
+			wantName:     "Some Show S01E02",
+			wantCategory: "TV SHOW",
+		},
+		"Movie": {
+			name:         "Film.2001.1080p.BluRay.x264-GRP",
+			wantName:     "Film (2001)",
+			wantCategory: "MOVIE",
+		},
+		"Plain": {
+			name:     "Some Album",
+			wantName: "Some Album",
+		},
+	}
+	for name, tt := range tests {
+		testData := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			release := ParseRelease(testData.name)
+			if got := release.FriendlyName(); got != testData.wantName {
+				t.Errorf("Release.FriendlyName() = %v, want %v", got, testData.wantName)
+			}
+			if got := release.Category(); got != testData.wantCategory {
+				t.Errorf("Release.Category() = %v, want %v", got, testData.wantCategory)
+			}
+		})
+	}
+}
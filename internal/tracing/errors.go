@@ -0,0 +1,45 @@
+package tracing
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	errMissingAddedTorrent = errors.New("transmission didn't return the added torrent")
+	errUnknownFreeSpace    = errors.New("transmission couldn't check the free space")
+)
+
+type unsupportedExporterError struct {
+	exporter string
+}
+
+func (u unsupportedExporterError) Error() string {
+	return fmt.Sprintf("unsupported trace exporter: %q", u.exporter)
+}
+
+type invalidEndpointError struct {
+	endpoint string
+}
+
+func (i invalidEndpointError) Error() string {
+	return fmt.Sprintf("otlp endpoint must be an http or https URL: %q", i.endpoint)
+}
+
+type unexpectedRPCStatusError struct {
+	method string
+	status int
+}
+
+func (u unexpectedRPCStatusError) Error() string {
+	return fmt.Sprintf("unexpected status calling %s: %d", u.method, u.status)
+}
+
+type rpcResultError struct {
+	method string
+	result string
+}
+
+func (r rpcResultError) Error() string {
+	return fmt.Sprintf("failed calling %s: %s", r.method, r.result)
+}
@@ -0,0 +1,39 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"github.com/bufbuild/connect-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Interceptor starts a span for every unary RPC, continuing the caller's trace if it sent one.
+func Interceptor() connect.UnaryInterceptorFunc {
+	tracer := Tracer("internal/tracing/rpc")
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (res connect.AnyResponse, err error) {
+			ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(req.Header()))
+			procedure := strings.TrimPrefix(req.Spec().Procedure, "/")
+			service, method, _ := strings.Cut(procedure, "/")
+			ctx, span := tracer.Start(ctx, procedure,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					attribute.String("rpc.system", "connect"),
+					attribute.String("rpc.service", service),
+					attribute.String("rpc.method", method),
+					attribute.String("net.sock.peer.addr", req.Peer().Addr),
+				),
+			)
+			defer func() { End(span, err) }()
+			res, err = next(ctx, req)
+			if err != nil {
+				span.SetAttributes(attribute.String("rpc.connect_rpc.error_code", connect.CodeOf(err).String()))
+			}
+			return res, err
+		}
+	}
+}
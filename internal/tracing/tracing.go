@@ -0,0 +1,143 @@
+// Package tracing exports OpenTelemetry traces of Discord interactions, RPCs,
+// transmission calls and database transactions.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+const (
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+)
+
+// Config is where traces are exported, tracing is disabled when Exporter is empty.
+type Config struct {
+	// Exporter is one of "otlp" or "stdout".
+	Exporter string
+	// Endpoint is the OTLP/HTTP collector, like "http://otel-collector:4318".
+	Endpoint string
+	// SampleRatio is the fraction of traces recorded, unless their parent was sampled.
+	SampleRatio float64 `map:"SAMPLE_RATIO"`
+}
+
+func (c Config) Valid() (errs []string) {
+	switch c.Exporter {
+	case "", ExporterStdout:
+	case ExporterOTLP:
+		if c.Endpoint == "" {
+			errs = append(errs, "Tracing Endpoint is required for the otlp exporter")
+		} else if _, err := otlpOptions(c.Endpoint); err != nil {
+			errs = append(errs, fmt.Sprintf("Tracing Endpoint is invalid: %v", err))
+		}
+	default:
+		errs = append(errs, fmt.Sprintf("Tracing Exporter is unsupported: %q", c.Exporter))
+	}
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		errs = append(errs, "Tracing Sample Ratio must be between 0 and 1")
+	}
+	return
+}
+
+const (
+	serviceName           = "polly"
+	instrumentationPrefix = "github.com/bobcob7/polly-bot/"
+)
+
+// Setup registers the global tracer provider and propagator,
+// the returned function flushes buffered spans and must be called before exiting.
+func Setup(cfg Config) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	var exporter sdktrace.SpanExporter
+	switch cfg.Exporter {
+	case "":
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		options, err := otlpOptions(cfg.Endpoint)
+		if err != nil {
+			return nil, err
+		}
+		exporter, err = otlptracehttp.New(context.Background(), options...)
+		if err != nil {
+			return nil, fmt.Errorf("failed creating otlp exporter: %w", err)
+		}
+	case ExporterStdout:
+		exporter, err = stdouttrace.New()
+		if err != nil {
+			return nil, fmt.Errorf("failed creating stdout exporter: %w", err)
+		}
+	default:
+		return nil, unsupportedExporterError{cfg.Exporter}
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName)))
+	if err != nil {
+		return nil, fmt.Errorf("failed creating trace resource: %w", err)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// otlpOptions sends spans to the collector's traces path below endpoint, which is a URL like "http://otel-collector:4318".
+func otlpOptions(endpoint string) ([]otlptracehttp.Option, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing otlp endpoint: %w", err)
+	}
+	if u.Host == "" {
+		return nil, invalidEndpointError{endpoint}
+	}
+	options := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(u.Host),
+		otlptracehttp.WithURLPath(path.Join("/", u.Path, "v1/traces")),
+	}
+	switch u.Scheme {
+	case "http":
+		options = append(options, otlptracehttp.WithInsecure())
+	case "https":
+	default:
+		return nil, invalidEndpointError{endpoint}
+	}
+	return options, nil
+}
+
+// Tracer traces a package of this module, pkg is its path within the module.
+func Tracer(pkg string) trace.Tracer {
+	return otel.Tracer(instrumentationPrefix + pkg)
+}
+
+// End records err on the span if it isn't nil, then ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Logger adds the trace ID of the span in ctx to the logger, so logs can be found from traces.
+func Logger(ctx context.Context, logger *zap.Logger) *zap.Logger {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return logger
+	}
+	return logger.With(zap.String("traceID", spanContext.TraceID().String()), zap.String("spanID", spanContext.SpanID().String()))
+}
@@ -0,0 +1,43 @@
+package tracing
+
+import "testing"
+
+func TestConfig_Valid(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		config Config
+		valid  bool
+	}{
+		"Disabled": {
+			config: Config{},
+			valid:  true,
+		},
+		"OTLP over HTTP": {
+			config: Config{Exporter: ExporterOTLP, Endpoint: "http://otel-collector:4318"},
+			valid:  true,
+		},
+		"OTLP below a path": {
+			config: Config{Exporter: ExporterOTLP, Endpoint: "https://collector.example.com/otlp/"},
+			valid:  true,
+		},
+		"OTLP without an endpoint": {
+			config: Config{Exporter: ExporterOTLP},
+		},
+		"OTLP without a scheme": {
+			config: Config{Exporter: ExporterOTLP, Endpoint: "otel-collector:4318"},
+		},
+		"Unsupported exporter": {
+			config: Config{Exporter: "jaeger"},
+		},
+	}
+	for name, tt := range tests {
+		testData := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			errs := testData.config.Valid()
+			if valid := len(errs) == 0; valid != testData.valid {
+				t.Errorf("Config.Valid() = %v, want valid %v", errs, testData.valid)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/bobcob7/transmission-rpc"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Transmission traces calls to the transmission RPC server.
+type Transmission struct {
+	client *transmission.Client
+	rpc    *rpcClient
+	tracer trace.Tracer
+}
+
+// NewTransmission wraps the client, endpoint is the same transmission endpoint the client uses.
+func NewTransmission(client *transmission.Client, endpoint string) *Transmission {
+	return &Transmission{
+		client: client,
+		rpc:    newRPCClient(endpoint),
+		tracer: Tracer("internal/tracing/transmission"),
+	}
+}
+
+func (t *Transmission) start(ctx context.Context, method string, ids []int) (context.Context, trace.Span) {
+	return t.tracer.Start(ctx, "transmission."+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("rpc.system", "transmission"),
+			attribute.String("rpc.method", method),
+			attribute.IntSlice("transmission.torrent_ids", ids),
+		),
+	)
+}
+
+func (t *Transmission) GetTorrents(ctx context.Context, ids ...int) (torrents []transmission.Torrent, err error) {
+	ctx, span := t.start(ctx, "GetTorrents", ids)
+	defer func() { End(span, err) }()
+	torrents, err = t.client.GetTorrents(ctx, ids...)
+	span.SetAttributes(attribute.Int("transmission.torrents", len(torrents)))
+	return torrents, err
+}
+
+func (t *Transmission) StopTorrents(ctx context.Context, ids ...int) (err error) {
+	ctx, span := t.start(ctx, "StopTorrents", ids)
+	defer func() { End(span, err) }()
+	return t.client.StopTorrents(ctx, ids...)
+}
+
+func (t *Transmission) RemoveTorrents(ctx context.Context, deleteLocalData bool, ids ...int) (err error) {
+	ctx, span := t.start(ctx, "RemoveTorrents", ids)
+	defer func() { End(span, err) }()
+	span.SetAttributes(attribute.Bool("transmission.delete_local_data", deleteLocalData))
+	return t.client.RemoveTorrents(ctx, deleteLocalData, ids...)
+}
@@ -0,0 +1,197 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const sessionIDHeader = "X-Transmission-Session-Id"
+
+// rpcClient makes the transmission RPC calls which the client library doesn't support.
+type rpcClient struct {
+	url    string
+	client *http.Client
+
+	lock      sync.Mutex
+	sessionID string
+}
+
+func newRPCClient(endpoint string) *rpcClient {
+	url := strings.TrimSuffix(endpoint, "/")
+	if !strings.HasSuffix(url, "/rpc") {
+		url += "/transmission/rpc"
+	}
+	return &rpcClient{
+		url:    url,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+type rpcRequest struct {
+	Method    string      `json:"method"`
+	Arguments interface{} `json:"arguments,omitempty"`
+}
+
+type rpcResponse struct {
+	Result    string          `json:"result"`
+	Arguments json.RawMessage `json:"arguments"`
+}
+
+// call sends the request, retrying once with a new session ID if transmission asks for one.
+func (r *rpcClient) call(ctx context.Context, method string, arguments, result interface{}) error {
+	body, err := json.Marshal(rpcRequest{Method: method, Arguments: arguments})
+	if err != nil {
+		return fmt.Errorf("failed encoding request: %w", err)
+	}
+	res, err := r.send(ctx, method, body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode == http.StatusConflict {
+		res.Body.Close()
+		r.lock.Lock()
+		r.sessionID = res.Header.Get(sessionIDHeader)
+		r.lock.Unlock()
+		if res, err = r.send(ctx, method, body); err != nil {
+			return err
+		}
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return unexpectedRPCStatusError{method: method, status: res.StatusCode}
+	}
+	var response rpcResponse
+	if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
+		return fmt.Errorf("failed decoding %s response: %w", method, err)
+	}
+	if response.Result != "success" {
+		return rpcResultError{method: method, result: response.Result}
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(response.Arguments, result); err != nil {
+		return fmt.Errorf("failed decoding %s arguments: %w", method, err)
+	}
+	return nil
+}
+
+func (r *rpcClient) send(ctx context.Context, method string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	r.lock.Lock()
+	req.Header.Set(sessionIDHeader, r.sessionID)
+	r.lock.Unlock()
+	res, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed calling %s: %w", method, err)
+	}
+	return res, nil
+}
+
+// NewTorrent is a torrent to add, either a magnet link or the contents of a .torrent file.
+type NewTorrent struct {
+	MagnetLink string
+	// Metainfo is passed on as is, so private torrents keep their trackers.
+	Metainfo []byte
+	// SubDir is a directory within transmission's download directory, the default is used if empty.
+	SubDir string
+	Paused bool
+}
+
+type torrentAddArguments struct {
+	Filename    string `json:"filename,omitempty"`
+	Metainfo    string `json:"metainfo,omitempty"`
+	DownloadDir string `json:"download-dir,omitempty"`
+	Paused      bool   `json:"paused,omitempty"`
+}
+
+type addedTorrent struct {
+	ID int `json:"id"`
+}
+
+type torrentAddResult struct {
+	Added     *addedTorrent `json:"torrent-added"`
+	Duplicate *addedTorrent `json:"torrent-duplicate"`
+}
+
+// AddTorrent adds the torrent, returning the ID of the existing torrent if it was already added.
+func (t *Transmission) AddTorrent(ctx context.Context, torrent NewTorrent) (id int, err error) {
+	ctx, span := t.start(ctx, "AddTorrent", nil)
+	defer func() { End(span, err) }()
+	args := torrentAddArguments{
+		Filename: torrent.MagnetLink,
+		Paused:   torrent.Paused,
+	}
+	if len(torrent.Metainfo) != 0 {
+		args.Filename = ""
+		args.Metainfo = base64.StdEncoding.EncodeToString(torrent.Metainfo)
+	}
+	if torrent.SubDir != "" {
+		downloadDir, err := t.downloadDir(ctx)
+		if err != nil {
+			return 0, err
+		}
+		args.DownloadDir = path.Join(downloadDir, torrent.SubDir)
+	}
+	var result torrentAddResult
+	if err := t.rpc.call(ctx, "torrent-add", args, &result); err != nil {
+		return 0, err
+	}
+	added := result.Added
+	if added == nil {
+		added = result.Duplicate
+	}
+	if added == nil {
+		return 0, errMissingAddedTorrent
+	}
+	span.SetAttributes(attribute.Int("transmission.torrent_id", added.ID))
+	return added.ID, nil
+}
+
+// downloadDir is transmission's default download directory.
+func (t *Transmission) downloadDir(ctx context.Context) (string, error) {
+	var session struct {
+		DownloadDir string `json:"download-dir"`
+	}
+	args := map[string][]string{"fields": {"download-dir"}}
+	if err := t.rpc.call(ctx, "session-get", args, &session); err != nil {
+		return "", err
+	}
+	return session.DownloadDir, nil
+}
+
+// FreeSpace is the number of bytes available in transmission's download directory.
+func (t *Transmission) FreeSpace(ctx context.Context) (free uint64, err error) {
+	ctx, span := t.start(ctx, "FreeSpace", nil)
+	defer func() { End(span, err) }()
+	downloadDir, err := t.downloadDir(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var result struct {
+		SizeBytes int64 `json:"size-bytes"`
+	}
+	if err := t.rpc.call(ctx, "free-space", map[string]string{"path": downloadDir}, &result); err != nil {
+		return 0, err
+	}
+	// Transmission reports -1 if it couldn't check
+	if result.SizeBytes < 0 {
+		return 0, errUnknownFreeSpace
+	}
+	span.SetAttributes(attribute.Int64("transmission.free_space", result.SizeBytes))
+	return uint64(result.SizeBytes), nil
+}
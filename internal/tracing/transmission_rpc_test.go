@@ -0,0 +1,70 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTransmission_AddTorrent(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		torrent NewTorrent
+		want    torrentAddArguments
+	}{
+		"Magnet link": {
+			torrent: NewTorrent{MagnetLink: "magnet:?xt=urn:btih:abc"},
+			want:    torrentAddArguments{Filename: "magnet:?xt=urn:btih:abc"},
+		},
+		"Metainfo in sub directory": {
+			torrent: NewTorrent{Metainfo: []byte("d4:infodee"), SubDir: "movie"},
+			want:    torrentAddArguments{Metainfo: "ZDQ6aW5mb2RlZQ==", DownloadDir: "/downloads/movie"},
+		},
+	}
+	for name, tt := range tests {
+		testData := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			var got torrentAddArguments
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get(sessionIDHeader) != "session" {
+					w.Header().Set(sessionIDHeader, "session")
+					w.WriteHeader(http.StatusConflict)
+					return
+				}
+				var req struct {
+					Method    string          `json:"method"`
+					Arguments json.RawMessage `json:"arguments"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Errorf("failed decoding request: %v", err)
+				}
+				switch req.Method {
+				case "session-get":
+					_, _ = w.Write([]byte(`{"result":"success","arguments":{"download-dir":"/downloads"}}`))
+				case "torrent-add":
+					if err := json.Unmarshal(req.Arguments, &got); err != nil {
+						t.Errorf("failed decoding arguments: %v", err)
+					}
+					_, _ = w.Write([]byte(`{"result":"success","arguments":{"torrent-added":{"id":7}}}`))
+				default:
+					_, _ = w.Write([]byte(`{"result":"method name not recognized"}`))
+				}
+			}))
+			defer server.Close()
+			tx := NewTransmission(nil, server.URL)
+			id, err := tx.AddTorrent(context.Background(), testData.torrent)
+			if err != nil {
+				t.Fatalf("Transmission.AddTorrent() error = %v", err)
+			}
+			if id != 7 {
+				t.Errorf("Transmission.AddTorrent() = %v, want %v", id, 7)
+			}
+			if got != testData.want {
+				t.Errorf("Transmission.AddTorrent() sent %+v, want %+v", got, testData.want)
+			}
+		})
+	}
+}
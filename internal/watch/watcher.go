@@ -0,0 +1,182 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bobcob7/polly-bot/internal/config"
+	"github.com/bobcob7/polly-bot/internal/downloads"
+	"github.com/bobcob7/polly-bot/internal/torrent"
+	"go.uber.org/zap"
+)
+
+const (
+	doneDirectory  = "done"
+	errorDirectory = "error"
+	torrentExt     = ".torrent"
+	magnetExt      = ".magnet"
+)
+
+// Watcher periodically scans a directory for .torrent and .magnet files and adds them.
+// Files in a subdirectory are added with the category named after it, e.g. tv-show/x.torrent.
+// Processed files are moved into done/ and files which couldn't be added into error/.
+// Files are only processed once they are unchanged between two scans, so they aren't read while being written.
+type Watcher struct {
+	logger  *zap.Logger
+	config  config.Watch
+	adder   *downloads.Adder
+	pending map[string]fileState
+}
+
+// fileState is what a scan saw of a file, to tell whether it is still being written.
+type fileState struct {
+	size     int64
+	modified time.Time
+}
+
+func NewWatcher(cfg config.Watch, adder *downloads.Adder) *Watcher {
+	return &Watcher{
+		logger:  zap.L().With(zap.String("component", "watch")),
+		config:  cfg,
+		adder:   adder,
+		pending: make(map[string]fileState),
+	}
+}
+
+func (w *Watcher) Run(ctx context.Context) error {
+	for _, dir := range []string{doneDirectory, errorDirectory} {
+		if err := os.MkdirAll(filepath.Join(w.config.Directory, dir), 0o755); err != nil {
+			return fmt.Errorf("failed creating %s directory: %w", dir, err)
+		}
+	}
+	ticker := time.NewTicker(w.config.Period)
+	defer ticker.Stop()
+	for {
+		if err := w.scan(ctx); err != nil {
+			w.logger.Error("failed to scan watch directory", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Watcher) scan(ctx context.Context) error {
+	files := make(map[string]fileState)
+	err := filepath.WalkDir(w.config.Directory, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != w.config.Directory && (d.Name() == doneDirectory || d.Name() == errorDirectory) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case torrentExt, magnetExt:
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			files[path] = fileState{size: info.Size(), modified: info.ModTime()}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed walking watch directory: %w", err)
+	}
+	for _, path := range w.settled(files) {
+		target := doneDirectory
+		if err := w.ingest(ctx, path); err != nil {
+			w.logger.Error("failed to add watched file", zap.String("path", path), zap.Error(err))
+			target = errorDirectory
+		}
+		if err := w.move(path, target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// settled returns the files which haven't changed since the last scan, the rest are remembered for the next.
+func (w *Watcher) settled(files map[string]fileState) []string {
+	paths := make([]string, 0)
+	for path, state := range files {
+		if previous, ok := w.pending[path]; ok && previous.size == state.size && previous.modified.Equal(state.modified) {
+			paths = append(paths, path)
+			delete(files, path)
+		}
+	}
+	w.pending = files
+	sort.Strings(paths)
+	return paths
+}
+
+func (w *Watcher) ingest(ctx context.Context, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed reading file: %w", err)
+	}
+	req := downloads.Request{}
+	if strings.EqualFold(filepath.Ext(path), torrentExt) {
+		metainfo, err := torrent.ParseMetainfo(data)
+		if err != nil {
+			return fmt.Errorf("failed parsing torrent file: %w", err)
+		}
+		// The file is added as is, a magnet link would lose the trackers private torrents need
+		req.Metainfo = data
+		req.FriendlyName = metainfo.Name
+	} else {
+		req.MagnetLink = strings.TrimSpace(string(data))
+		if name, err := torrent.MagnetURIDisplayName(req.MagnetLink); err == nil {
+			req.FriendlyName = name
+		}
+	}
+	if req.FriendlyName == "" {
+		req.FriendlyName = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if req.Category, err = w.category(path); err != nil {
+		return err
+	}
+	added, err := w.adder.Add(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed adding torrent: %w", err)
+	}
+	w.logger.Info("added watched file", zap.String("path", path), zap.String("torrentID", added.ID))
+	return nil
+}
+
+// category returns the category named by the first subdirectory of the path, if any.
+func (w *Watcher) category(path string) (string, error) {
+	rel, err := filepath.Rel(w.config.Directory, path)
+	if err != nil {
+		return "", fmt.Errorf("failed getting relative path: %w", err)
+	}
+	dir, _, ok := strings.Cut(filepath.ToSlash(rel), "/")
+	if !ok {
+		return "", nil
+	}
+	category, err := downloads.NormalizeCategory(strings.NewReplacer("-", " ", "_", " ").Replace(dir))
+	if err != nil {
+		return "", fmt.Errorf("failed getting category from directory: %w", err)
+	}
+	return category, nil
+}
+
+// move puts the file into the target directory, prefixed with the time to avoid collisions.
+func (w *Watcher) move(path, target string) error {
+	name := time.Now().UTC().Format("20060102T150405") + "-" + filepath.Base(path)
+	if err := os.Rename(path, filepath.Join(w.config.Directory, target, name)); err != nil {
+		return fmt.Errorf("failed moving file to %s: %w", target, err)
+	}
+	return nil
+}
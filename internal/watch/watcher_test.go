@@ -0,0 +1,87 @@
+package watch
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bobcob7/polly-bot/internal/config"
+)
+
+func TestWatcher_category(t *testing.T) {
+	t.Parallel()
+	w := &Watcher{config: config.Watch{Directory: "/watch"}}
+	tests := map[string]struct {
+		path    string
+		want    string
+		wantErr bool
+	}{
+		"Root": {
+			path: "/watch/a.torrent",
+			want: "",
+		},
+		"Category": {
+			path: "/watch/movie/a.torrent",
+			want: "MOVIE",
+		},
+		"Separated category": {
+			path: "/watch/tv-show/season/a.magnet",
+			want: "TV SHOW",
+		},
+		"Unknown category": {
+			path:    "/watch/other/a.torrent",
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		testData := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := w.category(filepath.FromSlash(testData.path))
+			if (err != nil) != testData.wantErr {
+				t.Errorf("category() error = %v, wantErr %v", err, testData.wantErr)
+				return
+			}
+			if got != testData.want {
+				t.Errorf("category() = %v, want %v", got, testData.want)
+			}
+		})
+	}
+}
+
+func TestWatcher_settled(t *testing.T) {
+	t.Parallel()
+	modified := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	w := &Watcher{pending: make(map[string]fileState)}
+	scans := []struct {
+		files map[string]fileState
+		want  []string
+	}{
+		{
+			files: map[string]fileState{
+				"a.torrent": {size: 10, modified: modified},
+				"b.torrent": {size: 10, modified: modified},
+			},
+			want: []string{},
+		},
+		{
+			files: map[string]fileState{
+				"a.torrent": {size: 10, modified: modified},
+				"b.torrent": {size: 20, modified: modified.Add(time.Second)},
+			},
+			want: []string{"a.torrent"},
+		},
+		{
+			files: map[string]fileState{
+				"b.torrent": {size: 20, modified: modified.Add(time.Second)},
+			},
+			want: []string{"b.torrent"},
+		},
+	}
+	for i, scan := range scans {
+		if got := w.settled(scan.files); !reflect.DeepEqual(got, scan.want) {
+			t.Errorf("scan %d: settled() = %v, want %v", i, got, scan.want)
+		}
+	}
+}
@@ -11,18 +11,32 @@ import (
 	"strings"
 	"syscall"
 
+	"github.com/bobcob7/polly-bot/internal/audit"
+	"github.com/bobcob7/polly-bot/internal/auth"
 	"github.com/bobcob7/polly-bot/internal/commands"
 	"github.com/bobcob7/polly-bot/internal/config"
+	"github.com/bobcob7/polly-bot/internal/downloads"
+	"github.com/bobcob7/polly-bot/internal/health"
 	"github.com/bobcob7/polly-bot/internal/mapper"
+	"github.com/bobcob7/polly-bot/internal/metrics"
+	"github.com/bobcob7/polly-bot/internal/organizer"
+	"github.com/bobcob7/polly-bot/internal/rss"
 	"github.com/bobcob7/polly-bot/internal/server"
+	"github.com/bobcob7/polly-bot/internal/tracing"
+	"github.com/bobcob7/polly-bot/internal/watch"
 	"github.com/bobcob7/polly-bot/pkg/discord"
+	"github.com/bobcob7/polly-bot/pkg/proto/downloads/v1/downloadsv1connect"
+	"github.com/bobcob7/polly-bot/pkg/proto/grpc/health/v1/healthv1connect"
 	"github.com/bobcob7/transmission-rpc"
+	"github.com/bufbuild/connect-go"
+	"github.com/bufbuild/connect-grpcreflect-go"
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
 	"github.com/golang-migrate/migrate/v4/source/iofs"
 	_ "github.com/lib/pq"
 	"github.com/upper/db/v4"
 	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
 )
 
 var showVersion bool
@@ -85,7 +99,7 @@ func main() {
 		fmt.Fprintln(os.Stderr, errs.Error())
 		os.Exit(1)
 	}
-	if cfg.Discord.GuildID == "" {
+	if len(cfg.Discord.Guilds()) == 0 && !cfg.Discord.Global {
 		guilds, err := discord.GetGuilds(cfg.Discord.Token)
 		if err != nil {
 			zap.L().Fatal("failed to get guilds", zap.Error(err))
@@ -95,30 +109,109 @@ func main() {
 		}
 		return
 	}
-	ctx, done := context.WithCancel(context.Background())
-	defer done()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		// Restore the default handling, so a second signal exits without waiting for the shutdown
+		stop()
+		zap.L().Info("Received interrupt, shutting down")
+	}()
 	// Start database connection
 	pool, err := getDatabase(cfg.Database)
 	if err != nil {
 		zap.L().Fatal("failed to connect to database", zap.Error(err))
 	}
+	shutdownTracing, err := tracing.Setup(cfg.Tracing)
+	if err != nil {
+		zap.L().Fatal("failed to setup tracing", zap.Error(err))
+	}
 	// Start transmission interface
-	transmissionClient, err := transmission.New(ctx, cfg.Transmission.Endpoint)
+	client, err := transmission.New(ctx, cfg.Transmission.Endpoint)
 	if err != nil {
 		zap.L().Fatal("failed to connect to transmission RPC server", zap.Error(err))
 	}
+	transmissionClient := tracing.NewTransmission(client, cfg.Transmission.Endpoint)
+	auditRecorder := audit.NewRecorder(pool, cfg.Access.AuditChannelID)
 	// Start transmission/db interface
-	srv := server.New(cfg, pool, transmissionClient)
-	go func() {
-		if err := srv.Run(ctx); err != nil {
-			zap.L().Fatal("failed to startup RPC server", zap.Error(err))
+	// Auditing comes before auth, so RPCs it rejects are audited too
+	interceptors := []connect.Interceptor{tracing.Interceptor(), metrics.Interceptor(), auditRecorder.Interceptor()}
+	if !cfg.GRPC.Auth.Disabled {
+		authenticators := []auth.Authenticator{auth.NewAPIKeys(pool)}
+		if cfg.GRPC.ClientCAFile != "" {
+			authenticators = append(authenticators, auth.NewClientCertificates(cfg.GRPC.Auth.ClientCertScopes, cfg.GRPC.Auth.RateLimit))
 		}
-	}()
+		interceptors = append(interceptors, auth.NewInterceptor(server.ProcedureScopes, cfg.GRPC.Auth.RateLimit, authenticators...))
+	}
+	diskGuard := downloads.NewDiskGuard(cfg.Transmission.DiskGuard, transmissionClient.FreeSpace)
+	quotas := downloads.NewQuotas(cfg.Quotas, cfg.Access, pool)
+	handlerOptions := connect.WithInterceptors(interceptors...)
+	srv := server.New(cfg, pool, transmissionClient, diskGuard, quotas, handlerOptions)
+	checker := health.NewChecker(cfg.Health.Timeout, downloadsv1connect.DownloadServiceName)
+	checker.Register(
+		health.ScrapeCheck(srv, cfg.Health.ScrapeMaxAge),
+		health.DatabaseCheck(pool),
+	)
+	srv.HandleOps("/metrics", metrics.Handler())
+	srv.HandleOps("/healthz", checker.Liveness())
+	srv.HandleOps("/readyz", checker.Readiness())
+	// gRPC health checks stay unauthenticated on the RPC server, like the standard protocol expects
+	srv.Handle(healthv1connect.NewHealthHandler(checker))
+	// Reflection goes through the same interceptors as RPCs, so it needs credentials
+	reflector := grpcreflect.NewStaticReflector(
+		downloadsv1connect.DownloadServiceName,
+		healthv1connect.HealthName,
+	)
+	srv.Handle(grpcreflect.NewHandlerV1(reflector, handlerOptions))
+	srv.Handle(grpcreflect.NewHandlerV1Alpha(reflector, handlerOptions))
+	// Every component stops when ctx is done or one of them fails
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.Go(func() error {
+		if err := srv.Run(groupCtx); err != nil {
+			return fmt.Errorf("failed running RPC server: %w", err)
+		}
+		return nil
+	})
 
 	getAll := commands.NewGetAllCommand(pool)
-	addTorrent := commands.NewAddCommand(pool, transmissionClient)
+	status := commands.NewStatusCommand(pool, cfg.Transmission.Scraper.RateWindow)
+	adder := downloads.NewAdder(pool, transmissionClient, diskGuard, quotas)
+	quotaCommand := commands.NewQuotaCommand(pool, quotas, cfg.Access)
+	historyCommand := commands.NewHistoryCommand(pool, cfg.Access)
+	auditCommand := commands.NewAuditCommand(pool, cfg.Access)
+	settingsCommand := commands.NewSettingsCommand(pool, cfg.Access)
+	apiKeyCommand := commands.NewAPIKeyCommand(pool, cfg.Access)
+	addTorrent := commands.NewAddCommand(adder, pool, cfg.Access)
+	rssCommand := commands.NewRSSCommand(pool, cfg.Access)
+	poller := rss.NewPoller(cfg.RSS, pool, adder)
+	group.Go(func() error {
+		if err := poller.Run(groupCtx); err != nil {
+			return fmt.Errorf("failed running rss poller: %w", err)
+		}
+		return nil
+	})
+	if cfg.Watch.Directory != "" {
+		watcher := watch.NewWatcher(cfg.Watch, adder)
+		group.Go(func() error {
+			if err := watcher.Run(groupCtx); err != nil {
+				return fmt.Errorf("failed running watcher: %w", err)
+			}
+			return nil
+		})
+	}
 	notifier := commands.NewTorrentNotifier(pool)
 	srv.SubscribeCompletedTorrents(notifier.CompletedTorrents)
+	libraryOrganizer := organizer.NewOrganizer(cfg.Organizer, cfg.Transmission.DownloadDirectory, pool)
+	srv.SubscribeCompletedTorrents(libraryOrganizer.CompletedTorrents)
+	libraryOrganizer.SubscribeExtractions(notifier.Extractions)
+	group.Go(func() error {
+		if err := libraryOrganizer.Run(groupCtx); err != nil {
+			return fmt.Errorf("failed running organizer: %w", err)
+		}
+		return nil
+	})
+	stalled := commands.NewStalledCommand(pool, transmissionClient, cfg.Transmission.Stalled.Window, cfg.Access)
+	srv.SubscribeStalledTorrents(stalled.StalledTorrents)
 
 	// Start discord interface
 	bot := discord.New(
@@ -128,33 +221,41 @@ func main() {
 		&commands.Echo{},
 		&commands.Ping{},
 		getAll,
+		status,
+		stalled,
 		addTorrent,
+		rssCommand,
+		quotaCommand,
+		historyCommand,
+		auditCommand,
+		settingsCommand,
+		apiKeyCommand,
 
 		// &transmission.AddDownload{Transmission: tr},
 		// &transmission.UnfinishedDownloads{Transmission: tr},
 		// &transmission.SubscribeDownloads{Transmission: tr},
 	)
 	bot.OnStartHook("torrentNotifier", notifier)
-	errChan := make(chan error, 1)
-	go func() {
-		defer close(errChan)
-		errChan <- bot.Run(ctx)
-	}()
-	stop := make(chan os.Signal, 1)
-	signal.Notify(stop, os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
-	for {
-		select {
-		case err, ok := <-errChan:
-			if !ok {
-				// Exit since the error channel is closed
-				return
-			}
-			if err != nil {
-				zap.L().Error("Error running bot", zap.Error(err))
-			}
-		case <-stop:
-			zap.L().Info("Received interrupt, exiting")
-			done()
+	bot.OnStartHook("stalledNotifier", stalled)
+	bot.OnStartHook("auditMirror", auditRecorder)
+	bot.SetAuditor(auditRecorder)
+	bot.SetObserver(metrics.Commands{})
+	checker.Register(health.DiscordCheck(bot))
+	group.Go(func() error {
+		if err := bot.Run(groupCtx); err != nil {
+			return fmt.Errorf("failed running bot: %w", err)
 		}
+		return nil
+	})
+	runErr := group.Wait()
+	flushCtx, flushed := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer flushed()
+	if err := shutdownTracing(flushCtx); err != nil {
+		zap.L().Error("failed to flush traces", zap.Error(err))
+	}
+	if runErr != nil {
+		zap.L().Error("Stopped with an error", zap.Error(runErr))
+		os.Exit(1)
 	}
+	zap.L().Info("Stopped")
 }
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS api_keys (
+	id VARCHAR(255) PRIMARY KEY NOT NULL,
+	name VARCHAR(255) NOT NULL,
+	secret_hash VARCHAR(255) NOT NULL,
+	scopes VARCHAR(255) NOT NULL,
+	rate_limit INT NOT NULL,
+	created_by VARCHAR(255) NOT NULL,
+	created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	last_used_at TIMESTAMP WITH TIME ZONE,
+	revoked_at TIMESTAMP WITH TIME ZONE
+);
@@ -0,0 +1,2 @@
+ALTER TABLE torrents ADD COLUMN IF NOT EXISTS notified_at TIMESTAMP WITH TIME ZONE;
+UPDATE torrents SET notified_at = completed_at WHERE completed_at IS NOT NULL;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS rss_failed_items;
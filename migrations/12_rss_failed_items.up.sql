@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS rss_failed_items (
+	feed_id VARCHAR(255) NOT NULL,
+	guid TEXT NOT NULL,
+	attempts INTEGER NOT NULL,
+	failed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	PRIMARY KEY(feed_id, guid),
+	CONSTRAINT fk_feed_id
+      FOREIGN KEY(feed_id) 
+	  	REFERENCES rss_feeds(id)
+);
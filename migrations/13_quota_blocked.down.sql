@@ -0,0 +1 @@
+ALTER TABLE user_quotas DROP COLUMN IF EXISTS blocked;
@@ -0,0 +1 @@
+ALTER TABLE user_quotas ADD COLUMN IF NOT EXISTS blocked BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS torrent_samples (
+	torrent_id BIGINT NOT NULL,
+	sampled_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	downloaded BIGINT NOT NULL,
+	uploaded BIGINT NOT NULL,
+	PRIMARY KEY(torrent_id, sampled_at),
+	CONSTRAINT fk_torrent_id
+      FOREIGN KEY(torrent_id) 
+	  	REFERENCES torrents(id)
+);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS rss_rule_episodes;
+DROP TABLE IF EXISTS rss_seen_items;
+DROP TABLE IF EXISTS rss_rules;
+DROP TABLE IF EXISTS rss_feeds;
@@ -0,0 +1,43 @@
+CREATE TABLE IF NOT EXISTS rss_feeds (
+	id VARCHAR(255) PRIMARY KEY NOT NULL,
+	url TEXT UNIQUE NOT NULL,
+	created_by VARCHAR(255) NOT NULL,
+	created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	polled_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE TABLE IF NOT EXISTS rss_rules (
+	id VARCHAR(255) PRIMARY KEY NOT NULL,
+	feed_id VARCHAR(255) NOT NULL,
+	pattern TEXT NOT NULL,
+	category VARCHAR(255),
+	min_size BIGINT NOT NULL,
+	max_size BIGINT NOT NULL,
+	dedupe_episodes BOOLEAN NOT NULL,
+	created_by VARCHAR(255) NOT NULL,
+	channel_id VARCHAR(255),
+	created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	CONSTRAINT fk_feed_id
+      FOREIGN KEY(feed_id) 
+	  	REFERENCES rss_feeds(id)
+);
+
+CREATE TABLE IF NOT EXISTS rss_seen_items (
+	feed_id VARCHAR(255) NOT NULL,
+	guid TEXT NOT NULL,
+	seen_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	PRIMARY KEY(feed_id, guid),
+	CONSTRAINT fk_feed_id
+      FOREIGN KEY(feed_id) 
+	  	REFERENCES rss_feeds(id)
+);
+
+CREATE TABLE IF NOT EXISTS rss_rule_episodes (
+	rule_id VARCHAR(255) NOT NULL,
+	episode VARCHAR(255) NOT NULL,
+	torrent_id BIGINT NOT NULL,
+	PRIMARY KEY(rule_id, episode),
+	CONSTRAINT fk_rule_id
+      FOREIGN KEY(rule_id) 
+	  	REFERENCES rss_rules(id)
+);
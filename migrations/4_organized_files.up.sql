@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS organized_files (
+	torrent_id BIGINT NOT NULL,
+	source_path TEXT NOT NULL,
+	destination_path TEXT NOT NULL,
+	mode VARCHAR(255) NOT NULL,
+	organized_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	PRIMARY KEY(torrent_id, destination_path),
+	CONSTRAINT fk_torrent_id
+      FOREIGN KEY(torrent_id) 
+	  	REFERENCES torrents(id)
+);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS user_quotas (
+	user_id VARCHAR(255) PRIMARY KEY NOT NULL,
+	max_active INT NOT NULL,
+	daily_bytes BIGINT NOT NULL,
+	weekly_bytes BIGINT NOT NULL,
+	updated_by VARCHAR(255) NOT NULL,
+	updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS user_downloads (
+	user_id VARCHAR(255) NOT NULL,
+	torrent_id BIGINT NOT NULL,
+	created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	PRIMARY KEY(user_id, torrent_id),
+	CONSTRAINT fk_torrent_id
+      FOREIGN KEY(torrent_id) 
+	  	REFERENCES torrents(id)
+);
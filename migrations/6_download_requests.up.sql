@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS download_requests (
+	id VARCHAR(255) PRIMARY KEY NOT NULL,
+	magnet_link TEXT NOT NULL,
+	friendly_name TEXT NOT NULL,
+	category VARCHAR(255),
+	requested_by VARCHAR(255) NOT NULL,
+	requester_roles TEXT NOT NULL,
+	channel_id VARCHAR(255),
+	status VARCHAR(255) NOT NULL,
+	decided_by VARCHAR(255),
+	decided_at TIMESTAMP WITH TIME ZONE,
+	torrent_id BIGINT,
+	created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS user_downloads (
+	user_id VARCHAR(255) NOT NULL,
+	torrent_id BIGINT NOT NULL,
+	created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	PRIMARY KEY(user_id, torrent_id),
+	CONSTRAINT fk_torrent_id
+      FOREIGN KEY(torrent_id) 
+	  	REFERENCES torrents(id)
+);
+
+INSERT INTO user_downloads (user_id, torrent_id, created_at)
+SELECT requested_by, id, created_at FROM torrents WHERE requested_by != '';
+
+ALTER TABLE torrents DROP COLUMN IF EXISTS requested_by;
@@ -0,0 +1,7 @@
+ALTER TABLE torrents ADD COLUMN IF NOT EXISTS requested_by VARCHAR(255) NOT NULL DEFAULT '';
+
+UPDATE torrents SET requested_by = user_downloads.user_id
+FROM user_downloads
+WHERE user_downloads.torrent_id = torrents.id;
+
+DROP TABLE IF EXISTS user_downloads;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS audit_events (
+	id BIGSERIAL PRIMARY KEY NOT NULL,
+	source VARCHAR(255) NOT NULL,
+	user_id VARCHAR(255) NOT NULL,
+	guild_id VARCHAR(255) NOT NULL,
+	action VARCHAR(255) NOT NULL,
+	options TEXT NOT NULL,
+	result VARCHAR(255) NOT NULL,
+	error TEXT NOT NULL,
+	created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS audit_events_created_at ON audit_events (created_at);
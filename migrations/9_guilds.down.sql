@@ -0,0 +1,5 @@
+ALTER TABLE download_requests DROP COLUMN IF EXISTS guild_id;
+ALTER TABLE rss_rules DROP COLUMN IF EXISTS guild_id;
+ALTER TABLE torrents DROP COLUMN IF EXISTS guild_id;
+
+DROP TABLE IF EXISTS guild_settings;
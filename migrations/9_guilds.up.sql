@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS guild_settings (
+	guild_id VARCHAR(255) PRIMARY KEY NOT NULL,
+	notification_channel_id VARCHAR(255) NOT NULL,
+	admin_roles TEXT NOT NULL,
+	categories TEXT NOT NULL,
+	updated_by VARCHAR(255) NOT NULL,
+	updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+ALTER TABLE torrents ADD COLUMN IF NOT EXISTS guild_id VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE rss_rules ADD COLUMN IF NOT EXISTS guild_id VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE download_requests ADD COLUMN IF NOT EXISTS guild_id VARCHAR(255) NOT NULL DEFAULT '';
@@ -0,0 +1,93 @@
+package discord
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+)
+
+// How a handler returned, as recorded in audit events and metrics.
+const (
+	ResultOK    = "ok"
+	ResultError = "error"
+	ResultPanic = "panic"
+)
+
+// AuditEvent records a command, modal or component someone ran and how it went.
+type AuditEvent struct {
+	UserID  string
+	GuildID string
+	// Action is the command name or custom ID.
+	Action string
+	// Options are the JSON encoded command options.
+	Options string
+	Result  string
+	Error   string
+}
+
+// Auditor records every interaction the bot handled.
+type Auditor interface {
+	Audit(ctx context.Context, event *AuditEvent)
+}
+
+func (b *Bot) SetAuditor(auditor Auditor) {
+	b.auditor = auditor
+}
+
+func (b *Bot) audit(ctx context.Context, handleContext Context, action string, options interface{}, err error) {
+	if b.auditor == nil {
+		return
+	}
+	event := &AuditEvent{
+		UserID:  handleContext.UserID(),
+		GuildID: handleContext.GuildID,
+		Action:  action,
+	}
+	rawOptions, encodeErr := json.Marshal(options)
+	if encodeErr != nil {
+		zap.L().Error("failed encoding audit options", zap.Error(encodeErr))
+	}
+	event.Options = string(rawOptions)
+	event.Result = handlerResult(err)
+	if err != nil {
+		event.Error = err.Error()
+	}
+	b.auditor.Audit(ctx, event)
+}
+
+// handlerResult describes how a handler returned, as recorded in audit events and metrics.
+func handlerResult(err error) string {
+	if err == nil {
+		return ResultOK
+	}
+	if errors.As(err, &panicError{}) {
+		return ResultPanic
+	}
+	return ResultError
+}
+
+// auditCommandOptions flattens command options into their values, nesting sub commands.
+func auditCommandOptions(options []*discordgo.ApplicationCommandInteractionDataOption) map[string]interface{} {
+	output := make(map[string]interface{}, len(options))
+	for _, option := range options {
+		switch option.Type {
+		case discordgo.ApplicationCommandOptionSubCommand, discordgo.ApplicationCommandOptionSubCommandGroup:
+			output[option.Name] = auditCommandOptions(option.Options)
+		default:
+			output[option.Name] = option.Value
+		}
+	}
+	return output
+}
+
+type panicError struct {
+	value interface{}
+}
+
+func (e panicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.value)
+}
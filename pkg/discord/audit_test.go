@@ -0,0 +1,52 @@
+package discord
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestAuditCommandOptions(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		options []*discordgo.ApplicationCommandInteractionDataOption
+		want    string
+	}{
+		"None": {
+			want: `{}`,
+		},
+		"Values": {
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				{Name: "finished", Type: discordgo.ApplicationCommandOptionBoolean, Value: true},
+				{Name: "name", Type: discordgo.ApplicationCommandOptionString, Value: "show"},
+			},
+			want: `{"finished":true,"name":"show"}`,
+		},
+		"SubCommand": {
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				{
+					Name: "set",
+					Type: discordgo.ApplicationCommandOptionSubCommand,
+					Options: []*discordgo.ApplicationCommandInteractionDataOption{
+						{Name: "max-active", Type: discordgo.ApplicationCommandOptionInteger, Value: float64(2)},
+					},
+				},
+			},
+			want: `{"set":{"max-active":2}}`,
+		},
+	}
+	for name, tt := range tests {
+		testData := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := json.Marshal(auditCommandOptions(testData.options))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != testData.want {
+				t.Errorf("auditCommandOptions() = %s, want %s", got, testData.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,9 @@ package discord
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync"
 
 	"github.com/bwmarrin/discordgo"
 	"go.uber.org/zap"
@@ -13,7 +16,110 @@ type Context struct {
 	*discordgo.Session
 	*discordgo.InteractionCreate
 	*PrivateMessenger
-	logger *zap.Logger
+	logger    *zap.Logger
+	responder *responder
+}
+
+// responder tracks how the interaction was acknowledged, it is shared by every copy of a Context.
+type responder struct {
+	mu           sync.Mutex
+	acknowledged bool
+	// deferredType is the deferred response sent for the interaction, zero if it wasn't deferred.
+	deferredType discordgo.InteractionResponseType
+}
+
+var (
+	errNoInteraction       = errors.New("context has no interaction to respond to")
+	errAlreadyAcknowledged = errors.New("interaction was already acknowledged")
+	errUnsupportedFollowUp = errors.New("response type can't follow an acknowledged interaction")
+)
+
+// Respond answers the interaction. Once the interaction was acknowledged, e.g. by being deferred,
+// messages edit the original response or are sent as follow-up messages instead.
+func (c *Context) Respond(resp *discordgo.InteractionResponse) error {
+	if c.responder == nil || c.Interaction == nil {
+		return errNoInteraction
+	}
+	c.responder.mu.Lock()
+	defer c.responder.mu.Unlock()
+	if !c.responder.acknowledged {
+		if err := c.Session.InteractionRespond(c.Interaction, resp); err != nil {
+			return err
+		}
+		c.responder.acknowledged = true
+		return nil
+	}
+	data := resp.Data
+	if data == nil {
+		data = &discordgo.InteractionResponseData{}
+	}
+	switch {
+	case resp.Type != discordgo.InteractionResponseChannelMessageWithSource && resp.Type != discordgo.InteractionResponseUpdateMessage:
+		return errUnsupportedFollowUp
+	case c.responder.deferredType == discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		resp.Type == discordgo.InteractionResponseUpdateMessage:
+		// Fill in the deferred message, or update the message the component belongs to
+		c.responder.deferredType = 0
+		if _, err := c.Session.InteractionResponseEdit(c.Interaction, &discordgo.WebhookEdit{
+			Content:    &data.Content,
+			Components: &data.Components,
+			Embeds:     &data.Embeds,
+		}); err != nil {
+			return fmt.Errorf("failed to edit response: %w", err)
+		}
+	default:
+		if _, err := c.Session.FollowupMessageCreate(c.Interaction, true, &discordgo.WebhookParams{
+			Content:    data.Content,
+			Components: data.Components,
+			Embeds:     data.Embeds,
+			Flags:      data.Flags,
+		}); err != nil {
+			return fmt.Errorf("failed to send follow-up: %w", err)
+		}
+	}
+	return nil
+}
+
+// Defer acknowledges the interaction, giving the handler up to 15 minutes to Respond.
+// Commands and modals show a loading message, which is only visible to the user if ephemeral.
+// Components keep their message until it is updated.
+func (c *Context) Defer(ephemeral bool) error {
+	if c.responder == nil || c.Interaction == nil {
+		return errNoInteraction
+	}
+	c.responder.mu.Lock()
+	defer c.responder.mu.Unlock()
+	if c.responder.acknowledged {
+		return errAlreadyAcknowledged
+	}
+	resp := &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{},
+	}
+	if c.Interaction.Type == discordgo.InteractionMessageComponent {
+		resp.Type = discordgo.InteractionResponseDeferredMessageUpdate
+	}
+	if ephemeral {
+		resp.Data.Flags = discordgo.MessageFlagsEphemeral
+	}
+	if err := c.Session.InteractionRespond(c.Interaction, resp); err != nil {
+		return fmt.Errorf("failed to defer response: %w", err)
+	}
+	c.responder.acknowledged = true
+	c.responder.deferredType = resp.Type
+	return nil
+}
+
+// Followup sends another message after the interaction was responded to.
+func (c *Context) Followup(content string, ephemeral bool) error {
+	data := &discordgo.InteractionResponseData{Content: content}
+	if ephemeral {
+		data.Flags = discordgo.MessageFlagsEphemeral
+	}
+	return c.Respond(&discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: data,
+	})
 }
 
 func (c *Context) Logger() *zap.Logger {
@@ -22,7 +128,7 @@ func (c *Context) Logger() *zap.Logger {
 
 func (c *Context) Error(err error) {
 	c.logger.Info("Failed with error", zap.Error(err))
-	if err := c.Session.InteractionRespond(c.Interaction, &discordgo.InteractionResponse{
+	if err := c.Respond(&discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
 			Title:   "Error",
@@ -55,3 +161,11 @@ func (c *Context) ChannelID() string {
 	}
 	return c.Interaction.ChannelID
 }
+
+// MemberRoles returns the role IDs of the guild member who caused the interaction.
+func (c *Context) MemberRoles() []string {
+	if c.Interaction == nil || c.Interaction.Member == nil {
+		return nil
+	}
+	return c.Interaction.Member.Roles
+}
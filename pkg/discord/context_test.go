@@ -0,0 +1,136 @@
+package discord
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// recordingTransport answers every Discord API request successfully, recording the method, path and response type.
+type recordingTransport struct {
+	mu       sync.Mutex
+	requests []string
+}
+
+func (r *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	request := req.Method + " " + req.URL.Path[strings.Index(req.URL.Path, "/interactions/")+1:]
+	if i := strings.Index(req.URL.Path, "/webhooks/"); i != -1 {
+		request = req.Method + " " + req.URL.Path[i+1:]
+	}
+	if req.Body != nil {
+		var body struct {
+			Type *int `json:"type"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&body); err == nil && body.Type != nil {
+			request += " " + strconv.Itoa(*body.Type)
+		}
+	}
+	r.mu.Lock()
+	r.requests = append(r.requests, request)
+	r.mu.Unlock()
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{}`)),
+		Request:    req,
+	}, nil
+}
+
+func TestContext_Respond(t *testing.T) {
+	t.Parallel()
+	message := &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{Content: "done"},
+	}
+	tests := map[string]struct {
+		interactionType discordgo.InteractionType
+		steps           func(ctx Context) error
+		want            []string
+	}{
+		"Respond": {
+			interactionType: discordgo.InteractionApplicationCommand,
+			steps: func(ctx Context) error {
+				return ctx.Respond(message)
+			},
+			want: []string{"POST interactions/1/token/callback 4"},
+		},
+		"Deferred command": {
+			interactionType: discordgo.InteractionApplicationCommand,
+			steps: func(ctx Context) error {
+				if err := ctx.Defer(true); err != nil {
+					return err
+				}
+				return ctx.Respond(message)
+			},
+			want: []string{"POST interactions/1/token/callback 5", "PATCH webhooks/app/token/messages/@original"},
+		},
+		"Deferred component": {
+			interactionType: discordgo.InteractionMessageComponent,
+			steps: func(ctx Context) error {
+				if err := ctx.Defer(true); err != nil {
+					return err
+				}
+				return ctx.Respond(message)
+			},
+			want: []string{"POST interactions/1/token/callback 6", "POST webhooks/app/token"},
+		},
+		"Follow-up": {
+			interactionType: discordgo.InteractionApplicationCommand,
+			steps: func(ctx Context) error {
+				if err := ctx.Respond(message); err != nil {
+					return err
+				}
+				return ctx.Followup("more", true)
+			},
+			want: []string{"POST interactions/1/token/callback 4", "POST webhooks/app/token"},
+		},
+		"Defer after responding": {
+			interactionType: discordgo.InteractionApplicationCommand,
+			steps: func(ctx Context) error {
+				if err := ctx.Respond(message); err != nil {
+					return err
+				}
+				if err := ctx.Defer(true); !errors.Is(err, errAlreadyAcknowledged) {
+					return err
+				}
+				return nil
+			},
+			want: []string{"POST interactions/1/token/callback 4"},
+		},
+	}
+	for name, tt := range tests {
+		testData := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			transport := &recordingTransport{}
+			session, err := discordgo.New("Bot token")
+			if err != nil {
+				t.Fatal(err)
+			}
+			session.Client = &http.Client{Transport: transport}
+			ctx := Context{
+				Session: session,
+				InteractionCreate: &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+					ID:    "1",
+					AppID: "app",
+					Token: "token",
+					Type:  testData.interactionType,
+				}},
+				responder: &responder{},
+			}
+			if err := testData.steps(ctx); err != nil {
+				t.Fatal(err)
+			}
+			if strings.Join(transport.requests, "\n") != strings.Join(testData.want, "\n") {
+				t.Errorf("requests = %q, want %q", transport.requests, testData.want)
+			}
+		})
+	}
+}
@@ -6,17 +6,51 @@ import (
 	"fmt"
 	"log"
 	"reflect"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/upper/db/v4"
 	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
 )
 
 type Config struct {
-	Token             string
-	GuildID           string `map:"GUILD_ID"`
-	PrivateChannelTTL int    `map:"PRIVATE_CHANNEL_TTL"`
+	Token string
+	// DeferAfter is how long handlers can take before their response is deferred, zero never defers.
+	// Discord requires a response within 3 seconds.
+	DeferAfter time.Duration `map:"DEFER_AFTER"`
+	// HandlerTimeout limits how long handlers can take, including after deferring.
+	HandlerTimeout time.Duration `map:"HANDLER_TIMEOUT"`
+	// GuildID is a single guild to register commands in, kept alongside GuildIDs for existing deployments.
+	GuildID  string   `map:"GUILD_ID"`
+	GuildIDs []string `map:"GUILD_IDS"`
+	// Global registers commands for every guild the bot is in, instead of per guild.
+	Global            bool
+	PrivateChannelTTL int `map:"PRIVATE_CHANNEL_TTL"`
+}
+
+// Guilds returns every configured guild ID, without duplicates.
+func (c Config) Guilds() []string {
+	output := make([]string, 0, len(c.GuildIDs)+1)
+	seen := make(map[string]bool, len(c.GuildIDs)+1)
+	for _, guildID := range append([]string{c.GuildID}, c.GuildIDs...) {
+		if guildID == "" || seen[guildID] {
+			continue
+		}
+		seen[guildID] = true
+		output = append(output, guildID)
+	}
+	return output
+}
+
+// commandScopes are the guild IDs to register commands in, an empty ID registers them globally.
+func (c Config) commandScopes() []string {
+	if c.Global {
+		return []string{""}
+	}
+	return c.Guilds()
 }
 
 func (c Config) Valid() (errs []string) {
@@ -26,15 +60,53 @@ func (c Config) Valid() (errs []string) {
 	if c.PrivateChannelTTL == 0 {
 		errs = append(errs, "Private Channel TTL is required")
 	}
+	if c.DeferAfter < 0 || c.DeferAfter >= interactionResponseWindow {
+		errs = append(errs, fmt.Sprintf("Defer After must be between 0 and %s", interactionResponseWindow))
+	}
+	if c.HandlerTimeout <= 0 {
+		errs = append(errs, "Handler Timeout must be positive")
+	}
 	return
 }
 
+// interactionResponseWindow is how long Discord waits for the initial response to an interaction.
+const interactionResponseWindow = 3 * time.Second
+
+// autoDefer defers the response if the handler hasn't responded within DeferAfter,
+// the returned function stops waiting and must be called once the handler returns.
+// The deferred response stays ephemeral or not once the handler responds, so it must match the handler's replies.
+func (b *Bot) autoDefer(handleContext Context, ephemeral bool) (stop func() bool) {
+	if b.config.DeferAfter == 0 {
+		return func() bool { return false }
+	}
+	timer := time.AfterFunc(b.config.DeferAfter, func() {
+		if err := handleContext.Defer(ephemeral); err != nil && !errors.Is(err, errAlreadyAcknowledged) {
+			handleContext.logger.Error("Failed to defer response", zap.Error(err))
+		}
+	})
+	return timer.Stop
+}
+
 type BaseCommand interface {
 	Name() string
 	Command() *discordgo.ApplicationCommand
 	Handle(ctx Context) error
 }
 
+// EphemeralCommand declares whether a command's replies are only visible to the user,
+// commands which don't implement it are assumed to reply ephemerally.
+type EphemeralCommand interface {
+	BaseCommand
+	Ephemeral() bool
+}
+
+func isEphemeral(command BaseCommand) bool {
+	if ephemeralCmd, ok := command.(EphemeralCommand); ok {
+		return ephemeralCmd.Ephemeral()
+	}
+	return true
+}
+
 type InitCommand interface {
 	BaseCommand
 	Run(ctx context.Context, s *discordgo.Session) error
@@ -46,9 +118,14 @@ type ModalCommand interface {
 	HandleModal(ctx Context, id string) error
 }
 
+type ComponentCommand interface {
+	BaseCommand
+	HasComponentID(id string) bool
+	HandleComponent(ctx Context, id string) error
+}
+
 type registeredCommand struct {
 	BaseCommand
-	id string
 }
 
 var ErrNotFound = errors.New("custom ID not found")
@@ -64,7 +141,6 @@ func (b *Bot) registerHandles(commands ...BaseCommand) {
 		if modalCmd, ok := baseInt.(BaseCommand); ok {
 			b.baseHandles[modalCmd.Name()] = registeredCommand{
 				BaseCommand: modalCmd,
-				id:          "",
 			}
 		}
 	}
@@ -90,6 +166,17 @@ func (b *Bot) registerHandles(commands ...BaseCommand) {
 			b.modalHandles[modalCmd.Name()] = modalCmd
 		}
 	}
+	b.componentHandles = make(map[string]ComponentCommand, len(commands))
+	for _, rawCommand := range commands {
+		base := reflect.ValueOf(rawCommand)
+		if !base.IsValid() {
+			panic("invalid component handler")
+		}
+		baseInt := base.Interface()
+		if componentCmd, ok := baseInt.(ComponentCommand); ok {
+			b.componentHandles[componentCmd.Name()] = componentCmd
+		}
+	}
 }
 
 type Bot struct {
@@ -99,7 +186,14 @@ type Bot struct {
 	baseHandles      map[string]registeredCommand
 	initHandles      map[string]InitCommand
 	modalHandles     map[string]ModalCommand
+	componentHandles map[string]ComponentCommand
 	onStartHooks     map[string]Starter
+	auditor          Auditor
+	observer         Observer
+	handlers         sync.WaitGroup
+	handlersLock     sync.Mutex
+	draining         bool
+	connected        atomic.Bool
 }
 
 func New(config Config, sess db.Session, cmds ...BaseCommand) *Bot {
@@ -116,6 +210,8 @@ func New(config Config, sess db.Session, cmds ...BaseCommand) *Bot {
 	return b
 }
 
+// Starter is run once the session is open, OnStart blocks until ctx is done
+// and an error stops the bot.
 type Starter interface {
 	OnStart(ctx Context, s *discordgo.Session) error
 }
@@ -164,6 +260,78 @@ func errorResponse(s *discordgo.Session, i *discordgo.Interaction, err error) {
 	}
 }
 
+func shuttingDownResponse(s *discordgo.Session, i *discordgo.Interaction) {
+	if err := s.InteractionRespond(i, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags:   discordgo.MessageFlagsEphemeral,
+			Content: "Polly is restarting, try again in a moment",
+		},
+	}); err != nil {
+		zap.L().Error("Failed to respond while shutting down", zap.Error(err))
+	}
+}
+
+// handle runs a handler for the interaction with a timeout, recovering panics and responding with any error.
+// The interaction is traced, observed and audited as action, kind names the interaction in panic responses.
+// If the handler is slow its response is deferred, ephemerally unless the command replies publicly.
+func (b *Bot) handle(handlerCtx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, logger *zap.Logger, kind string, command BaseCommand, action string, options interface{}, handler func(Context) error) {
+	name := command.Name()
+	handleContext := Context{
+		Session:           s,
+		InteractionCreate: i,
+		PrivateMessenger:  &b.privateMessenger,
+		responder:         &responder{},
+	}
+	handleContext.logger = logger.With(zap.String("userID", handleContext.UserID()))
+	var done context.CancelFunc
+	handleContext.Context, done = context.WithTimeout(handlerCtx, b.config.HandlerTimeout)
+	defer done()
+	span := startSpan(&handleContext, name)
+	start := time.Now()
+	stopDefer := b.autoDefer(handleContext, isEphemeral(command))
+	var handleErr error
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				handleErr = panicError{r}
+				logger.Error("Recovering from panic", zap.Error(handleErr))
+				_ = handleContext.Respond(&discordgo.InteractionResponse{
+					Type: discordgo.InteractionResponseChannelMessageWithSource,
+					Data: &discordgo.InteractionResponseData{
+						Flags:   discordgo.MessageFlagsEphemeral,
+						Title:   "Panic",
+						Content: fmt.Sprintf("Panic while processing %s: %s", kind, r),
+					},
+				})
+			}
+		}()
+		if err := handler(handleContext); err != nil {
+			handleErr = err
+			logger.Info("Handler error", zap.Error(err))
+			var msg string
+			//nolint: errorlint
+			if pubErr, ok := err.(interface{ Public() string }); ok {
+				msg = pubErr.Public()
+			} else {
+				msg = err.Error()
+			}
+			_ = handleContext.Respond(&discordgo.InteractionResponse{
+				Type: discordgo.InteractionResponseChannelMessageWithSource,
+				Data: &discordgo.InteractionResponseData{
+					Flags:   discordgo.MessageFlagsEphemeral,
+					Title:   "Error",
+					Content: msg,
+				},
+			})
+		}
+	}()
+	stopDefer()
+	endSpan(span, handleErr)
+	b.observe(name, start, handleErr)
+	b.audit(handlerCtx, handleContext, action, options, handleErr)
+}
+
 var errMissingMessageMember = errors.New("missing message member")
 var errMissingToken = errors.New("missing token")
 
@@ -176,60 +344,26 @@ func (b *Bot) Run(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to create discord session %w", err)
 	}
+	// Handlers aren't cancelled on shutdown, they are drained instead and bounded by HandlerTimeout
+	handlerCtx := context.Background()
 	// Add handler callbacks
 	session.AddHandler(func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		if !b.track() {
+			shuttingDownResponse(s, i.Interaction)
+			return
+		}
+		defer b.handlers.Done()
 		switch i.Type {
 		case discordgo.InteractionApplicationCommand:
 			logger := zap.L().With(zap.String("guildID", i.GuildID), zap.String("commandName", i.ApplicationCommandData().Name))
 			if h, ok := b.baseHandles[i.ApplicationCommandData().Name]; ok {
 				logger.Info("Handling command")
-				handleContext := Context{
-					Session:           s,
-					InteractionCreate: i,
-					PrivateMessenger:  &b.privateMessenger,
-				}
 				if i.Member == nil || i.Member.User == nil {
 					// Message member doesn't exist
 					errorResponse(s, i.Interaction, errMissingMessageMember)
 					return
 				}
-				handleContext.logger = logger.With(zap.String("userID", i.Member.User.ID))
-				var done context.CancelFunc
-				handleContext.Context, done = context.WithTimeout(ctx, time.Second*10)
-				defer done()
-				func() {
-					defer func() {
-						if r := recover(); r != nil {
-							logger.Error("Recovering from panic", zap.Error(err))
-							_ = handleContext.Session.InteractionRespond(handleContext.Interaction, &discordgo.InteractionResponse{
-								Type: discordgo.InteractionResponseChannelMessageWithSource,
-								Data: &discordgo.InteractionResponseData{
-									Flags:   discordgo.MessageFlagsEphemeral,
-									Title:   "Panic",
-									Content: fmt.Sprintf("Panic while processing command: %s", r),
-								},
-							})
-						}
-					}()
-					if err := h.Handle(handleContext); err != nil {
-						logger.Info("Handler error", zap.Error(err))
-						var msg string
-						//nolint: errorlint
-						if pubErr, ok := err.(interface{ Public() string }); ok {
-							msg = pubErr.Public()
-						} else {
-							msg = err.Error()
-						}
-						_ = handleContext.Session.InteractionRespond(handleContext.Interaction, &discordgo.InteractionResponse{
-							Type: discordgo.InteractionResponseChannelMessageWithSource,
-							Data: &discordgo.InteractionResponseData{
-								Flags:   discordgo.MessageFlagsEphemeral,
-								Title:   "Error",
-								Content: msg,
-							},
-						})
-					}
-				}()
+				b.handle(handlerCtx, s, i, logger, "command", h.BaseCommand, i.ApplicationCommandData().Name, auditCommandOptions(i.ApplicationCommandData().Options), h.Handle)
 			} else {
 				logger.Error("failed to find command")
 			}
@@ -246,124 +380,143 @@ func (b *Bot) Run(ctx context.Context) error {
 			}
 			if handle != nil {
 				logger.Info("Handling modal submission")
-				handleContext := Context{
-					Session:           s,
-					InteractionCreate: i,
-					PrivateMessenger:  &b.privateMessenger,
-				}
 				if i.Member == nil || i.Member.User == nil {
 					// Message member doesn't exist
 					errorResponse(s, i.Interaction, errMissingMessageMember)
 					return
 				}
-				handleContext.logger = logger.With(zap.String("userID", i.Member.User.ID))
-				var done context.CancelFunc
-				handleContext.Context, done = context.WithTimeout(ctx, time.Second*10)
-				defer done()
-				func() {
-					defer func() {
-						if r := recover(); r != nil {
-							_ = handleContext.Session.InteractionRespond(handleContext.Interaction, &discordgo.InteractionResponse{
-								Type: discordgo.InteractionResponseChannelMessageWithSource,
-								Data: &discordgo.InteractionResponseData{
-									Flags:   discordgo.MessageFlagsEphemeral,
-									Title:   "Panic",
-									Content: fmt.Sprintf("Panic while processing modal: %s", r),
-								},
-							})
-						}
-					}()
-					// Get Modal handle
-					if err := handle.HandleModal(handleContext, customID); err != nil {
-						var msg string
-						//nolint: errorlint
-						if pubErr, ok := err.(interface{ Public() string }); ok {
-							msg = pubErr.Public()
-						} else {
-							msg = err.Error()
-						}
-						_ = handleContext.Session.InteractionRespond(handleContext.Interaction, &discordgo.InteractionResponse{
-							Type: discordgo.InteractionResponseChannelMessageWithSource,
-							Data: &discordgo.InteractionResponseData{
-								Flags:   discordgo.MessageFlagsEphemeral,
-								Title:   "Error",
-								Content: msg,
-							},
-						})
-					}
-				}()
+				b.handle(handlerCtx, s, i, logger, "modal", handle, customID, modalValues(i.ModalSubmitData()), func(ctx Context) error {
+					return handle.HandleModal(ctx, customID)
+				})
 			} else {
 				logger.Error("failed to find interaction")
 			}
+
+		case discordgo.InteractionMessageComponent:
+			customID := i.Interaction.MessageComponentData().CustomID
+			logger := zap.L().With(zap.String("guildID", i.GuildID), zap.String("customID", customID))
+			var handle ComponentCommand
+			for _, h := range b.componentHandles {
+				if h.HasComponentID(customID) {
+					handle = h
+					break
+				}
+			}
+			if handle == nil {
+				logger.Error("failed to find component")
+				return
+			}
+			logger.Info("Handling message component")
+			// Components can be attached to private messages, which have no member
+			if (&Context{InteractionCreate: i}).UserID() == "" {
+				errorResponse(s, i.Interaction, errMissingMessageMember)
+				return
+			}
+			b.handle(handlerCtx, s, i, logger, "component", handle, customID, i.MessageComponentData().Values, func(ctx Context) error {
+				return handle.HandleComponent(ctx, customID)
+			})
 		}
 	})
 	// Add ready callback
 	session.AddHandler(func(s *discordgo.Session, r *discordgo.Ready) {
 		log.Printf("Logged in as: %v#%v", s.State.User.Username, s.State.User.Discriminator)
 	})
+	// Track the gateway connection, the session reconnects on its own
+	session.AddHandler(func(s *discordgo.Session, c *discordgo.Connect) {
+		b.connected.Store(true)
+	})
+	session.AddHandler(func(s *discordgo.Session, d *discordgo.Disconnect) {
+		b.connected.Store(false)
+	})
+	defer b.connected.Store(false)
 	// Open session
 	if err := session.Open(); err != nil {
-		log.Fatalf("Cannot open the session: %v", err)
+		return fmt.Errorf("failed to open discord session: %w", err)
 	}
 	defer session.Close()
-	errChan := make(chan error, len(b.baseHandles))
-	// Register application commands
-	for name, v := range b.baseHandles {
-		command := v.Command()
-		command.Name = name
-		cmd, err := session.ApplicationCommandCreate(session.State.User.ID, b.config.GuildID, command)
-		if err != nil {
-			return fmt.Errorf("failed to create %s command %w", name, err)
-		}
-		v.id = cmd.ID
-		// If the command is an advanced command, start it
-		base := reflect.ValueOf(v.BaseCommand)
-		if base.IsValid() {
-			baseInt := base.Interface()
-			if advanced, ok := baseInt.(InitCommand); ok {
-				go func() {
-					err := advanced.Run(ctx, session)
-					errChan <- err
-				}()
-			}
-		}
+	// Commands are left registered on shutdown, so they keep working across restarts
+	if err := b.syncCommands(session); err != nil {
+		return err
 	}
-	// Cleanup Session
-	defer func() {
-		for _, v := range b.baseHandles {
-			err := session.ApplicationCommandDelete(session.State.User.ID, b.config.GuildID, v.id)
-			if err != nil {
-				zap.L().Error("failed to delete command", zap.String("name", v.Name()), zap.Error(err))
+	group, groupCtx := errgroup.WithContext(ctx)
+	for name, command := range b.initHandles {
+		name, command := name, command
+		group.Go(func() error {
+			if err := command.Run(groupCtx, session); err != nil {
+				return fmt.Errorf("failed running command %q: %w", name, err)
 			}
-		}
-	}()
+			return nil
+		})
+	}
 	// Run onStart hooks
 	for name, hook := range b.onStartHooks {
+		name, hook := name, hook
 		hookContext := Context{
-			Context:          ctx,
+			Context:          groupCtx,
 			Session:          session,
 			PrivateMessenger: &b.privateMessenger,
 			logger:           zap.L().With(zap.String("onStartHook", name)),
 		}
-		if err := hook.OnStart(hookContext, session); err != nil {
-			return fmt.Errorf("failed onStart hook %q: %w", name, err)
-		}
+		group.Go(func() error {
+			if err := hook.OnStart(hookContext, session); err != nil {
+				return fmt.Errorf("failed onStart hook %q: %w", name, err)
+			}
+			return nil
+		})
 	}
-	// Wait for context to be cancelled
-	ticker := time.NewTicker(time.Second * 10)
-	defer ticker.Stop()
-botLoop:
-	for {
-		select {
-		case <-ctx.Done():
-			break botLoop
-		case <-ticker.C:
-			if err := b.privateMessenger.garbageCollect(ctx, session); err != nil {
-				zap.L().Error("Error collecting private messenger garbage", zap.Error(err))
+	group.Go(func() error {
+		ticker := time.NewTicker(time.Second * 10)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-groupCtx.Done():
+				return nil
+			case <-ticker.C:
+				if err := b.privateMessenger.garbageCollect(groupCtx, session); err != nil {
+					zap.L().Error("Error collecting private messenger garbage", zap.Error(err))
+				}
 			}
-		case err := <-errChan:
-			zap.L().Error("Error running command handler", zap.Error(err))
 		}
+	})
+	err = group.Wait()
+	if !b.drain(b.config.HandlerTimeout) {
+		zap.L().Warn("Stopped before every handler finished")
+	}
+	return err
+}
+
+// Connected is whether the Discord gateway is connected.
+func (b *Bot) Connected() bool {
+	return b.connected.Load()
+}
+
+// track registers an in-flight handler, it returns false once the bot is draining.
+func (b *Bot) track() bool {
+	b.handlersLock.Lock()
+	defer b.handlersLock.Unlock()
+	if b.draining {
+		return false
+	}
+	b.handlers.Add(1)
+	return true
+}
+
+// drain stops new handlers and waits for in-flight ones, it returns false if they didn't finish in time.
+func (b *Bot) drain(timeout time.Duration) bool {
+	b.handlersLock.Lock()
+	b.draining = true
+	b.handlersLock.Unlock()
+	finished := make(chan struct{})
+	go func() {
+		b.handlers.Wait()
+		close(finished)
+	}()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-finished:
+		return true
+	case <-timer.C:
+		return false
 	}
-	return nil
 }
@@ -0,0 +1,82 @@
+package discord
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConfig_commandScopes(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		config Config
+		want   []string
+	}{
+		"None": {
+			want: []string{},
+		},
+		"Single guild": {
+			config: Config{GuildID: "1"},
+			want:   []string{"1"},
+		},
+		"Multiple guilds": {
+			config: Config{GuildID: "1", GuildIDs: []string{"2", "1", "3"}},
+			want:   []string{"1", "2", "3"},
+		},
+		"Global": {
+			config: Config{GuildIDs: []string{"2"}, Global: true},
+			want:   []string{""},
+		},
+	}
+	for name, tt := range tests {
+		testData := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if got := testData.config.commandScopes(); !reflect.DeepEqual(got, testData.want) {
+				t.Errorf("Config.commandScopes() = %v, want %v", got, testData.want)
+			}
+		})
+	}
+}
+
+type testCommand struct {
+	BaseCommand
+}
+
+type testEphemeralCommand struct {
+	BaseCommand
+	ephemeral bool
+}
+
+func (c testEphemeralCommand) Ephemeral() bool {
+	return c.ephemeral
+}
+
+func Test_isEphemeral(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		command BaseCommand
+		want    bool
+	}{
+		"Undeclared": {
+			command: testCommand{},
+			want:    true,
+		},
+		"Ephemeral": {
+			command: testEphemeralCommand{ephemeral: true},
+			want:    true,
+		},
+		"Public": {
+			command: testEphemeralCommand{},
+			want:    false,
+		},
+	}
+	for name, tt := range tests {
+		testData := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if got := isEphemeral(testData.command); got != testData.want {
+				t.Errorf("isEphemeral() = %v, want %v", got, testData.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package discord
+
+import "time"
+
+// Observer records how each interaction the bot handled returned and how long it took.
+type Observer interface {
+	ObserveCommand(command, result string, duration time.Duration)
+}
+
+func (b *Bot) SetObserver(observer Observer) {
+	b.observer = observer
+}
+
+// observe records the handler's result and how long it took.
+func (b *Bot) observe(command string, start time.Time, err error) {
+	if b.observer == nil {
+		return
+	}
+	b.observer.ObserveCommand(command, handlerResult(err), time.Since(start))
+}
@@ -0,0 +1,373 @@
+package discord
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// UserID, ChannelID and RoleID fields declare user, channel and role options, see CommandOptions.
+type (
+	UserID    string
+	ChannelID string
+	RoleID    string
+)
+
+type missingOptionError struct {
+	name string
+}
+
+func (m missingOptionError) Error() string {
+	return fmt.Sprintf("option %q is required", m.name)
+}
+
+type invalidOptionError struct {
+	name   string
+	reason string
+}
+
+func (i invalidOptionError) Error() string {
+	return fmt.Sprintf("option %q %s", i.name, i.reason)
+}
+
+type missingInputError struct {
+	customID string
+}
+
+func (m missingInputError) Error() string {
+	return fmt.Sprintf("modal input %q is missing", m.customID)
+}
+
+// Options gives typed access to the options of a command, or of its sub command.
+// Missing options and options of an unexpected type return the default value.
+type Options struct {
+	subCommand []string
+	byName     map[string]*discordgo.ApplicationCommandInteractionDataOption
+}
+
+// Options returns the options of the command, descending into its sub command group and sub command.
+func (c *Context) Options() Options {
+	if c.Interaction == nil || c.Interaction.Type != discordgo.InteractionApplicationCommand {
+		return newOptions(nil)
+	}
+	return newOptions(c.Interaction.ApplicationCommandData().Options)
+}
+
+func newOptions(options []*discordgo.ApplicationCommandInteractionDataOption) Options {
+	output := Options{}
+	for len(options) == 1 &&
+		(options[0].Type == discordgo.ApplicationCommandOptionSubCommand || options[0].Type == discordgo.ApplicationCommandOptionSubCommandGroup) {
+		output.subCommand = append(output.subCommand, options[0].Name)
+		options = options[0].Options
+	}
+	output.byName = make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(options))
+	for _, option := range options {
+		output.byName[option.Name] = option
+	}
+	return output
+}
+
+// SubCommand returns the invoked sub command, prefixed by its group if it has one.
+func (o Options) SubCommand() string {
+	return strings.Join(o.subCommand, " ")
+}
+
+func (o Options) Has(name string) bool {
+	_, ok := o.byName[name]
+	return ok
+}
+
+func (o Options) String(name, def string) string {
+	if option, ok := o.byName[name]; ok {
+		if value, ok := option.Value.(string); ok {
+			return value
+		}
+	}
+	return def
+}
+
+func (o Options) Int(name string, def int64) int64 {
+	if option, ok := o.byName[name]; ok {
+		if value, ok := option.Value.(float64); ok {
+			return int64(value)
+		}
+	}
+	return def
+}
+
+func (o Options) Float(name string, def float64) float64 {
+	if option, ok := o.byName[name]; ok {
+		if value, ok := option.Value.(float64); ok {
+			return value
+		}
+	}
+	return def
+}
+
+func (o Options) Bool(name string, def bool) bool {
+	if option, ok := o.byName[name]; ok {
+		if value, ok := option.Value.(bool); ok {
+			return value
+		}
+	}
+	return def
+}
+
+// UserID returns the ID of the user option, or an empty string if it is missing.
+func (o Options) UserID(name string) string {
+	return o.String(name, "")
+}
+
+// ChannelID returns the ID of the channel option, or an empty string if it is missing.
+func (o Options) ChannelID(name string) string {
+	return o.String(name, "")
+}
+
+// RoleID returns the ID of the role option, or an empty string if it is missing.
+func (o Options) RoleID(name string) string {
+	return o.String(name, "")
+}
+
+// ModalValue returns the value of the modal's text input with the custom ID.
+func (c *Context) ModalValue(customID string) (string, error) {
+	if c.Interaction == nil || c.Interaction.Type != discordgo.InteractionModalSubmit {
+		return "", missingInputError{customID}
+	}
+	value, ok := modalValues(c.Interaction.ModalSubmitData())[customID]
+	if !ok {
+		return "", missingInputError{customID}
+	}
+	return value, nil
+}
+
+// modalValues returns the values of the text inputs in a modal submission by custom ID.
+func modalValues(data discordgo.ModalSubmitInteractionData) map[string]string {
+	output := make(map[string]string)
+	for _, component := range data.Components {
+		row, ok := component.(*discordgo.ActionsRow)
+		if !ok {
+			continue
+		}
+		for _, rowComponent := range row.Components {
+			if input, ok := rowComponent.(*discordgo.TextInput); ok {
+				output[input.CustomID] = input.Value
+			}
+		}
+	}
+	return output
+}
+
+var (
+	userIDType    = reflect.TypeOf(UserID(""))
+	channelIDType = reflect.TypeOf(ChannelID(""))
+	roleIDType    = reflect.TypeOf(RoleID(""))
+)
+
+// optionField is an option declared by a struct field.
+type optionField struct {
+	index       int
+	name        string
+	description string
+	optionType  discordgo.ApplicationCommandOptionType
+	required    bool
+	def         string
+	min         *float64
+	max         *float64
+	choices     []string
+}
+
+// optionFields parses the option declarations of a struct, panicking if they are invalid
+// since they are fixed at compile time.
+//
+// Fields are declared with the tags:
+//   - option: the option name, fields without it are ignored
+//   - description: shown by Discord
+//   - required: "true" if the option must be set
+//   - default: the value used when the option isn't set
+//   - min, max: bounds of integer and number options, max can't be zero since
+//     discordgo omits a zero MaxValue so Discord would never enforce it
+//   - choices: the "|" separated values a string option can take
+func optionFields(t reflect.Type) []optionField {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("options must be declared by a struct, not %s", t))
+	}
+	output := make([]optionField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, ok := field.Tag.Lookup("option")
+		if !ok {
+			continue
+		}
+		option := optionField{
+			index:       i,
+			name:        name,
+			description: field.Tag.Get("description"),
+			required:    field.Tag.Get("required") == "true",
+			def:         field.Tag.Get("default"),
+		}
+		switch {
+		case field.Type == userIDType:
+			option.optionType = discordgo.ApplicationCommandOptionUser
+		case field.Type == channelIDType:
+			option.optionType = discordgo.ApplicationCommandOptionChannel
+		case field.Type == roleIDType:
+			option.optionType = discordgo.ApplicationCommandOptionRole
+		case field.Type.Kind() == reflect.String:
+			option.optionType = discordgo.ApplicationCommandOptionString
+		case field.Type.Kind() == reflect.Bool:
+			option.optionType = discordgo.ApplicationCommandOptionBoolean
+		case field.Type.Kind() >= reflect.Int && field.Type.Kind() <= reflect.Uint64:
+			option.optionType = discordgo.ApplicationCommandOptionInteger
+		case field.Type.Kind() == reflect.Float32 || field.Type.Kind() == reflect.Float64:
+			option.optionType = discordgo.ApplicationCommandOptionNumber
+		default:
+			panic(fmt.Sprintf("option %q has unsupported type %s", name, field.Type))
+		}
+		for tag, bound := range map[string]**float64{"min": &option.min, "max": &option.max} {
+			if raw, ok := field.Tag.Lookup(tag); ok {
+				value, err := strconv.ParseFloat(raw, 64)
+				if err != nil {
+					panic(fmt.Sprintf("option %q has invalid %s %q", name, tag, raw))
+				}
+				if tag == "max" && value == 0 {
+					panic(fmt.Sprintf("option %q can't have a max of zero", name))
+				}
+				*bound = &value
+			}
+		}
+		if choices, ok := field.Tag.Lookup("choices"); ok {
+			option.choices = strings.Split(choices, "|")
+		}
+		output = append(output, option)
+	}
+	return output
+}
+
+// CommandOptions builds the option definitions declared by the struct, see optionFields for its tags.
+func CommandOptions(v interface{}) []*discordgo.ApplicationCommandOption {
+	fields := optionFields(reflect.TypeOf(v))
+	output := make([]*discordgo.ApplicationCommandOption, 0, len(fields))
+	for _, field := range fields {
+		option := &discordgo.ApplicationCommandOption{
+			Type:        field.optionType,
+			Name:        field.name,
+			Description: field.description,
+			Required:    field.required,
+			MinValue:    field.min,
+		}
+		if field.max != nil {
+			option.MaxValue = *field.max
+		}
+		for _, choice := range field.choices {
+			option.Choices = append(option.Choices, &discordgo.ApplicationCommandOptionChoice{Name: choice, Value: choice})
+		}
+		output = append(output, option)
+	}
+	return output
+}
+
+// Decode sets the fields of the struct pointer from the options it declares, see CommandOptions.
+func (o Options) Decode(v interface{}) error {
+	target := reflect.ValueOf(v)
+	if target.Kind() != reflect.Pointer || target.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("options must be decoded into a struct pointer, not %T", v))
+	}
+	target = target.Elem()
+	for _, field := range optionFields(target.Type()) {
+		if err := o.decodeField(field, target.Field(field.index)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o Options) decodeField(field optionField, value reflect.Value) error {
+	var raw interface{}
+	if option, ok := o.byName[field.name]; ok {
+		raw = option.Value
+	} else if field.required {
+		return missingOptionError{field.name}
+	} else if field.def == "" {
+		return nil
+	} else {
+		var err error
+		if raw, err = parseDefault(field); err != nil {
+			return err
+		}
+	}
+	switch field.optionType {
+	case discordgo.ApplicationCommandOptionBoolean:
+		b, ok := raw.(bool)
+		if !ok {
+			return invalidOptionError{field.name, "must be true or false"}
+		}
+		value.SetBool(b)
+	case discordgo.ApplicationCommandOptionInteger, discordgo.ApplicationCommandOptionNumber:
+		number, ok := raw.(float64)
+		if !ok {
+			return invalidOptionError{field.name, "must be a number"}
+		}
+		if field.min != nil && number < *field.min {
+			return invalidOptionError{field.name, fmt.Sprintf("must be at least %v", *field.min)}
+		}
+		if field.max != nil && number > *field.max {
+			return invalidOptionError{field.name, fmt.Sprintf("must be at most %v", *field.max)}
+		}
+		switch {
+		case value.CanInt():
+			value.SetInt(int64(number))
+		case value.CanUint():
+			if number < 0 {
+				return invalidOptionError{field.name, "must not be negative"}
+			}
+			value.SetUint(uint64(number))
+		default:
+			value.SetFloat(number)
+		}
+	default:
+		s, ok := raw.(string)
+		if !ok {
+			return invalidOptionError{field.name, "must be text"}
+		}
+		if len(field.choices) != 0 && !containsString(field.choices, s) {
+			return invalidOptionError{field.name, "must be one of " + strings.Join(field.choices, ", ")}
+		}
+		value.SetString(s)
+	}
+	return nil
+}
+
+// parseDefault converts a default tag into the type Discord sends for the option.
+func parseDefault(field optionField) (interface{}, error) {
+	switch field.optionType {
+	case discordgo.ApplicationCommandOptionBoolean:
+		b, err := strconv.ParseBool(field.def)
+		if err != nil {
+			return nil, invalidOptionError{field.name, "has an invalid default"}
+		}
+		return b, nil
+	case discordgo.ApplicationCommandOptionInteger, discordgo.ApplicationCommandOptionNumber:
+		number, err := strconv.ParseFloat(field.def, 64)
+		if err != nil {
+			return nil, invalidOptionError{field.name, "has an invalid default"}
+		}
+		return number, nil
+	default:
+		return field.def, nil
+	}
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+package discord
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+type testOptions struct {
+	Name    string  `option:"name" description:"Name" required:"true"`
+	Size    int     `option:"size" description:"Size" default:"20" min:"1" max:"50"`
+	Bytes   uint64  `option:"bytes" description:"Bytes"`
+	Ratio   float64 `option:"ratio" description:"Ratio"`
+	Enabled bool    `option:"enabled" description:"Enabled" default:"true"`
+	Kind    string  `option:"kind" description:"Kind" choices:"movie|show"`
+	User    UserID  `option:"user" description:"User"`
+	Ignored string
+}
+
+func TestCommandOptions(t *testing.T) {
+	t.Parallel()
+	min, max := 1.0, 50.0
+	want := []*discordgo.ApplicationCommandOption{
+		{Type: discordgo.ApplicationCommandOptionString, Name: "name", Description: "Name", Required: true},
+		{Type: discordgo.ApplicationCommandOptionInteger, Name: "size", Description: "Size", MinValue: &min, MaxValue: max},
+		{Type: discordgo.ApplicationCommandOptionInteger, Name: "bytes", Description: "Bytes"},
+		{Type: discordgo.ApplicationCommandOptionNumber, Name: "ratio", Description: "Ratio"},
+		{Type: discordgo.ApplicationCommandOptionBoolean, Name: "enabled", Description: "Enabled"},
+		{Type: discordgo.ApplicationCommandOptionString, Name: "kind", Description: "Kind", Choices: []*discordgo.ApplicationCommandOptionChoice{
+			{Name: "movie", Value: "movie"},
+			{Name: "show", Value: "show"},
+		}},
+		{Type: discordgo.ApplicationCommandOptionUser, Name: "user", Description: "User"},
+	}
+	if got := CommandOptions(testOptions{}); !reflect.DeepEqual(got, want) {
+		t.Errorf("CommandOptions() = %v, want %v", got, want)
+	}
+}
+
+func TestCommandOptions_zeroMax(t *testing.T) {
+	t.Parallel()
+	defer func() {
+		if recover() == nil {
+			t.Errorf("CommandOptions() didn't panic on a max of zero")
+		}
+	}()
+	CommandOptions(struct {
+		Offset int `option:"offset" description:"Offset" max:"0"`
+	}{})
+}
+
+func TestOptions_Decode(t *testing.T) {
+	t.Parallel()
+	option := func(name string, value interface{}) *discordgo.ApplicationCommandInteractionDataOption {
+		return &discordgo.ApplicationCommandInteractionDataOption{Name: name, Value: value}
+	}
+	subCommand := func(name string, options ...*discordgo.ApplicationCommandInteractionDataOption) *discordgo.ApplicationCommandInteractionDataOption {
+		return &discordgo.ApplicationCommandInteractionDataOption{Name: name, Type: discordgo.ApplicationCommandOptionSubCommand, Options: options}
+	}
+	tests := map[string]struct {
+		options        []*discordgo.ApplicationCommandInteractionDataOption
+		want           testOptions
+		wantSubCommand string
+		wantErr        error
+	}{
+		"Defaults": {
+			options: []*discordgo.ApplicationCommandInteractionDataOption{option("name", "a")},
+			want:    testOptions{Name: "a", Size: 20, Enabled: true},
+		},
+		"All set": {
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				option("name", "a"),
+				option("size", float64(3)),
+				option("bytes", float64(1024)),
+				option("ratio", 1.5),
+				option("enabled", false),
+				option("kind", "show"),
+				option("user", "1234"),
+			},
+			want: testOptions{Name: "a", Size: 3, Bytes: 1024, Ratio: 1.5, Kind: "show", User: "1234"},
+		},
+		"Sub command": {
+			options:        []*discordgo.ApplicationCommandInteractionDataOption{subCommand("set", option("name", "a"))},
+			want:           testOptions{Name: "a", Size: 20, Enabled: true},
+			wantSubCommand: "set",
+		},
+		"Missing required": {
+			options: []*discordgo.ApplicationCommandInteractionDataOption{option("size", float64(3))},
+			wantErr: missingOptionError{"name"},
+		},
+		"Too large": {
+			options: []*discordgo.ApplicationCommandInteractionDataOption{option("name", "a"), option("size", float64(51))},
+			wantErr: invalidOptionError{"size", "must be at most 50"},
+		},
+		"Negative unsigned": {
+			options: []*discordgo.ApplicationCommandInteractionDataOption{option("name", "a"), option("bytes", float64(-1))},
+			wantErr: invalidOptionError{"bytes", "must not be negative"},
+		},
+		"Unknown choice": {
+			options: []*discordgo.ApplicationCommandInteractionDataOption{option("name", "a"), option("kind", "book")},
+			wantErr: invalidOptionError{"kind", "must be one of movie, show"},
+		},
+		"Wrong type": {
+			options: []*discordgo.ApplicationCommandInteractionDataOption{option("name", float64(1))},
+			wantErr: invalidOptionError{"name", "must be text"},
+		},
+	}
+	for name, tt := range tests {
+		testData := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			options := newOptions(testData.options)
+			var got testOptions
+			err := options.Decode(&got)
+			if !errors.Is(err, testData.wantErr) {
+				t.Fatalf("Options.Decode() error = %v, want %v", err, testData.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got != testData.want {
+				t.Errorf("Options.Decode() = %+v, want %+v", got, testData.want)
+			}
+			if options.SubCommand() != testData.wantSubCommand {
+				t.Errorf("Options.SubCommand() = %q, want %q", options.SubCommand(), testData.wantSubCommand)
+			}
+		})
+	}
+}
@@ -39,6 +39,12 @@ func (p *PrivateMessenger) garbageCollect(ctx context.Context, dis *discordgo.Se
 }
 
 func (p *PrivateMessenger) SendMessage(ctx Context, recipientID, content string) error {
+	return p.SendComplexMessage(ctx, recipientID, &discordgo.MessageSend{
+		Content: content,
+	})
+}
+
+func (p *PrivateMessenger) SendComplexMessage(ctx Context, recipientID string, data *discordgo.MessageSend) error {
 	var channel *models.PrivateChannel
 	if err := p.sess.Tx(func(sess db.Session) error {
 		var err error
@@ -64,7 +70,7 @@ func (p *PrivateMessenger) SendMessage(ctx Context, recipientID, content string)
 	}); err != nil {
 		return fmt.Errorf("failed db transaction: %w", err)
 	}
-	if _, err := ctx.Session.ChannelMessageSend(channel.ID, content); err != nil {
+	if _, err := ctx.Session.ChannelMessageSendComplex(channel.ID, data); err != nil {
 		return fmt.Errorf("failed sending private message: %w", err)
 	}
 	if err := channel.Bump(ctx, p.sess); err != nil {
@@ -0,0 +1,113 @@
+package discord
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+)
+
+// syncCommands reconciles the registered application commands in every scope with the bot's commands.
+// Commands are overwritten in bulk only when a definition changed, which also removes unknown commands.
+func (b *Bot) syncCommands(session *discordgo.Session) error {
+	desired := make([]*discordgo.ApplicationCommand, 0, len(b.baseHandles))
+	for name, v := range b.baseHandles {
+		command := v.Command()
+		command.Name = name
+		desired = append(desired, command)
+	}
+	appID := session.State.User.ID
+	for _, guildID := range b.config.commandScopes() {
+		logger := zap.L().With(zap.String("guildID", guildID))
+		existing, err := session.ApplicationCommands(appID, guildID)
+		if err != nil {
+			return fmt.Errorf("failed to get commands in guild %q: %w", guildID, err)
+		}
+		changed, err := commandsChanged(existing, desired)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			logger.Info("Application commands are up to date", zap.Int("commands", len(desired)))
+			continue
+		}
+		if _, err := session.ApplicationCommandBulkOverwrite(appID, guildID, desired); err != nil {
+			return fmt.Errorf("failed to overwrite commands in guild %q: %w", guildID, err)
+		}
+		logger.Info("Overwrote application commands", zap.Int("existing", len(existing)), zap.Int("commands", len(desired)))
+	}
+	return nil
+}
+
+// commandsChanged reports whether the existing commands differ from the desired ones, ignoring order.
+func commandsChanged(existing, desired []*discordgo.ApplicationCommand) (bool, error) {
+	if len(existing) != len(desired) {
+		return true, nil
+	}
+	existingDefinitions := make(map[string]string, len(existing))
+	for _, command := range existing {
+		definition, err := commandDefinition(command)
+		if err != nil {
+			return false, err
+		}
+		existingDefinitions[command.Name] = definition
+	}
+	for _, command := range desired {
+		definition, err := commandDefinition(command)
+		if err != nil {
+			return false, err
+		}
+		if existingDefinitions[command.Name] != definition {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// commandDefinition encodes the parts of a command Discord stores, with defaults filled in
+// so commands fetched from Discord compare equal to the ones built locally.
+func commandDefinition(command *discordgo.ApplicationCommand) (string, error) {
+	normalized := &discordgo.ApplicationCommand{
+		Type:                     command.Type,
+		Name:                     command.Name,
+		NameLocalizations:        command.NameLocalizations,
+		DefaultMemberPermissions: command.DefaultMemberPermissions,
+		Description:              command.Description,
+		DescriptionLocalizations: command.DescriptionLocalizations,
+		Options:                  normalizeOptions(command.Options),
+	}
+	if normalized.Type == 0 {
+		normalized.Type = discordgo.ChatApplicationCommand
+	}
+	if command.DMPermission != nil && !*command.DMPermission {
+		normalized.DMPermission = command.DMPermission
+	}
+	if command.NSFW != nil && *command.NSFW {
+		normalized.NSFW = command.NSFW
+	}
+	definition, err := json.Marshal(normalized)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode command %q: %w", command.Name, err)
+	}
+	return string(definition), nil
+}
+
+func normalizeOptions(options []*discordgo.ApplicationCommandOption) []*discordgo.ApplicationCommandOption {
+	if len(options) == 0 {
+		return nil
+	}
+	output := make([]*discordgo.ApplicationCommandOption, 0, len(options))
+	for _, option := range options {
+		normalized := *option
+		normalized.Options = normalizeOptions(option.Options)
+		if len(normalized.ChannelTypes) == 0 {
+			normalized.ChannelTypes = nil
+		}
+		if len(normalized.Choices) == 0 {
+			normalized.Choices = nil
+		}
+		output = append(output, &normalized)
+	}
+	return output
+}
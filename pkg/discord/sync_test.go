@@ -0,0 +1,105 @@
+package discord
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestCommandsChanged(t *testing.T) {
+	t.Parallel()
+	local := func() []*discordgo.ApplicationCommand {
+		return []*discordgo.ApplicationCommand{
+			{
+				Name:        "status",
+				Description: "Shows speeds and ETAs of torrents",
+				Options: []*discordgo.ApplicationCommandOption{
+					{Type: discordgo.ApplicationCommandOptionBoolean, Name: "all", Description: "Include finished torrents"},
+				},
+			},
+			{Name: "ping", Description: "Ping"},
+		}
+	}
+	// Discord fills in IDs, the command type and empty lists
+	dmPermission := true
+	fetched := func() []*discordgo.ApplicationCommand {
+		return []*discordgo.ApplicationCommand{
+			{ID: "2", Version: "1", Type: discordgo.ChatApplicationCommand, Name: "ping", Description: "Ping", DMPermission: &dmPermission, Options: []*discordgo.ApplicationCommandOption{}},
+			{
+				ID:          "1",
+				Version:     "1",
+				Type:        discordgo.ChatApplicationCommand,
+				Name:        "status",
+				Description: "Shows speeds and ETAs of torrents",
+				Options: []*discordgo.ApplicationCommandOption{
+					{Type: discordgo.ApplicationCommandOptionBoolean, Name: "all", Description: "Include finished torrents", ChannelTypes: []discordgo.ChannelType{}},
+				},
+			},
+		}
+	}
+	tests := map[string]struct {
+		existing func() []*discordgo.ApplicationCommand
+		desired  func() []*discordgo.ApplicationCommand
+		want     bool
+	}{
+		"Unchanged": {
+			existing: fetched,
+			desired:  local,
+			want:     false,
+		},
+		"Nothing registered": {
+			existing: func() []*discordgo.ApplicationCommand { return nil },
+			desired:  local,
+			want:     true,
+		},
+		"Description changed": {
+			existing: fetched,
+			desired: func() []*discordgo.ApplicationCommand {
+				commands := local()
+				commands[1].Description = "Pong"
+				return commands
+			},
+			want: true,
+		},
+		"Option added": {
+			existing: fetched,
+			desired: func() []*discordgo.ApplicationCommand {
+				commands := local()
+				commands[1].Options = []*discordgo.ApplicationCommandOption{
+					{Type: discordgo.ApplicationCommandOptionString, Name: "message", Description: "Message"},
+				}
+				return commands
+			},
+			want: true,
+		},
+		"Command removed": {
+			existing: fetched,
+			desired: func() []*discordgo.ApplicationCommand {
+				return local()[:1]
+			},
+			want: true,
+		},
+		"Command renamed": {
+			existing: fetched,
+			desired: func() []*discordgo.ApplicationCommand {
+				commands := local()
+				commands[1].Name = "pong"
+				return commands
+			},
+			want: true,
+		},
+	}
+	for name, tt := range tests {
+		testData := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := commandsChanged(testData.existing(), testData.desired())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != testData.want {
+				t.Errorf("commandsChanged() = %v, want %v", got, testData.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package discord
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// tracer uses the global tracer provider, so spans are only exported once the application sets one up.
+var tracer = otel.Tracer("github.com/bobcob7/polly-bot/pkg/discord")
+
+// startSpan traces the interaction handled by command, and adds the trace ID to the context's logger.
+func startSpan(handleContext *Context, command string) trace.Span {
+	var span trace.Span
+	handleContext.Context, span = tracer.Start(handleContext.Context, "discord."+command,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("discord.interaction_type", handleContext.Type.String()),
+			attribute.String("discord.guild_id", handleContext.GuildID),
+			attribute.String("discord.user_id", handleContext.UserID()),
+		),
+	)
+	if spanContext := span.SpanContext(); spanContext.IsValid() {
+		handleContext.logger = handleContext.logger.With(zap.String("traceID", spanContext.TraceID().String()), zap.String("spanID", spanContext.SpanID().String()))
+	}
+	return span
+}
+
+// endSpan records err on the span if it isn't nil, then ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -25,6 +25,22 @@ const (
 	DownloadServiceName = "downloads.v1.DownloadService"
 )
 
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// DownloadServiceDeleteDownloadProcedure is the fully-qualified name of the DownloadService's
+	// DeleteDownload RPC.
+	DownloadServiceDeleteDownloadProcedure = "/downloads.v1.DownloadService/DeleteDownload"
+	// DownloadServiceGetDownloadsProcedure is the fully-qualified name of the DownloadService's
+	// GetDownloads RPC.
+	DownloadServiceGetDownloadsProcedure = "/downloads.v1.DownloadService/GetDownloads"
+)
+
 // DownloadServiceClient is a client for the downloads.v1.DownloadService service.
 type DownloadServiceClient interface {
 	DeleteDownload(context.Context, *connect_go.Request[v1.DeleteDownloadRequest]) (*connect_go.Response[v1.DeleteDownloadResponse], error)
@@ -43,12 +59,12 @@ func NewDownloadServiceClient(httpClient connect_go.HTTPClient, baseURL string,
 	return &downloadServiceClient{
 		deleteDownload: connect_go.NewClient[v1.DeleteDownloadRequest, v1.DeleteDownloadResponse](
 			httpClient,
-			baseURL+"/downloads.v1.DownloadService/DeleteDownload",
+			baseURL+DownloadServiceDeleteDownloadProcedure,
 			opts...,
 		),
 		getDownloads: connect_go.NewClient[v1.GetDownloadsRequest, v1.GetDownloadsResponse](
 			httpClient,
-			baseURL+"/downloads.v1.DownloadService/GetDownloads",
+			baseURL+DownloadServiceGetDownloadsProcedure,
 			opts...,
 		),
 	}
@@ -83,13 +99,13 @@ type DownloadServiceHandler interface {
 // and JSON codecs. They also support gzip compression.
 func NewDownloadServiceHandler(svc DownloadServiceHandler, opts ...connect_go.HandlerOption) (string, http.Handler) {
 	mux := http.NewServeMux()
-	mux.Handle("/downloads.v1.DownloadService/DeleteDownload", connect_go.NewUnaryHandler(
-		"/downloads.v1.DownloadService/DeleteDownload",
+	mux.Handle(DownloadServiceDeleteDownloadProcedure, connect_go.NewUnaryHandler(
+		DownloadServiceDeleteDownloadProcedure,
 		svc.DeleteDownload,
 		opts...,
 	))
-	mux.Handle("/downloads.v1.DownloadService/GetDownloads", connect_go.NewUnaryHandler(
-		"/downloads.v1.DownloadService/GetDownloads",
+	mux.Handle(DownloadServiceGetDownloadsProcedure, connect_go.NewUnaryHandler(
+		DownloadServiceGetDownloadsProcedure,
 		svc.GetDownloads,
 		opts...,
 	))